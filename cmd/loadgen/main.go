@@ -0,0 +1,69 @@
+package main
+
+import (
+	"backend-wolt-go/internal/loadgen"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// main replays a load test scenario against a running server and prints the latency percentiles,
+// error rate and throughput. Flags override the corresponding fields of the scenario file.
+func main() {
+	scenarioPath := flag.String("scenario", "configs/loadgen.yaml", "Scenario file")
+	target := flag.String("target", "", "Base URL of the service, overriding the scenario")
+	rate := flag.Float64("rate", 0, "Requests per second, overriding the scenario")
+	duration := flag.Duration("duration", 0, "Duration of the run, overriding the scenario")
+	seed := flag.Uint64("seed", 0, "Seed of the request generator, overriding the scenario")
+	asJSON := flag.Bool("json", false, "Print the report as JSON")
+	flag.Parse()
+
+	scenario, err := loadgen.LoadScenario(*scenarioPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load scenario: %v\n", err)
+		os.Exit(1)
+	}
+	if *target != "" {
+		scenario.Target = *target
+	}
+	if *rate > 0 {
+		scenario.Rate = *rate
+	}
+	if *duration > 0 {
+		scenario.Duration = *duration
+	}
+	if *seed > 0 {
+		scenario.Seed = *seed
+	}
+	if err := scenario.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid scenario: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Stop starting new requests on Ctrl-C, but still report the completed ones.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Allow as many idle connections as the open-loop load may need, to measure the service
+	// rather than connection setup.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 1000
+	report := loadgen.Run(ctx, scenario, &http.Client{Transport: transport})
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+		os.Exit(1)
+	}
+}
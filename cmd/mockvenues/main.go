@@ -0,0 +1,73 @@
+package main
+
+import (
+	"backend-wolt-go/internal/mockvenue"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// main serves a mock of the Home Assignment venue API from a fixture directory, so that the
+// server can be run and tested without network access. Point api.base_url at its address.
+func main() {
+	addr := flag.String("addr", ":8001", "Address to listen on")
+	fixtures := flag.String("fixtures", "configs/venues", "Directory holding <slug>/static.json and <slug>/dynamic.json")
+	latency := flag.Duration("latency", 0, "Delay added to every response")
+	errorRate := flag.Float64("error-rate", 0, "Fraction of requests answered with 500")
+	malformedRate := flag.Float64("malformed-rate", 0, "Fraction of requests answered with malformed JSON")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	venues, err := mockvenue.LoadDir(*fixtures)
+	if err != nil {
+		logger.Error("failed to load fixtures", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Inject the configured faults; the latency applies to every response, faulty or not.
+	api := mockvenue.New(venues)
+	switch {
+	case *errorRate > 0:
+		api.SetFault("", mockvenue.Fault{Latency: *latency, Status: http.StatusInternalServerError, Probability: *errorRate})
+	case *malformedRate > 0:
+		api.SetFault("", mockvenue.Fault{Latency: *latency, Malformed: true, Probability: *malformedRate})
+	case *latency > 0:
+		api.SetFault("", mockvenue.Fault{Latency: *latency})
+	}
+	if *errorRate > 0 && *malformedRate > 0 {
+		logger.Warn("only one of -error-rate and -malformed-rate can be applied, using -error-rate")
+	}
+
+	srv := &http.Server{Addr: *addr, Handler: api}
+	logger.Info("serving mock venue API", slog.String("addr", *addr), slog.Int("venues", len(venues)))
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		logger.Error("could not listen", slog.String("addr", *addr), slog.String("error", err.Error()))
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("graceful shutdown failed", slog.String("error", err.Error()))
+	}
+}
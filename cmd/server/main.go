@@ -1,16 +1,20 @@
 package main
 
 import (
-	"backend-wolt-go/internal/api"
-	"backend-wolt-go/internal/client"
-	"backend-wolt-go/internal/service"
+	"backend-wolt-go/internal/app"
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/tracing"
 	"backend-wolt-go/internal/utils"
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
-
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 // main is the entry point of the application. It initializes the configuration, services, and HTTP router,
@@ -22,36 +26,107 @@ func main() {
 		log.Fatalf("failed to load configuration: %v", err)
 	}
 
-	// Initialize the venue provider service with the base URL from the configuration.
-	venueProvider := service.NewVenueProvider(config.API.BaseURL)
-
-	// Create a new DOPC (Delivery Order Price Calculator) service client using the venue provider.
-	dopcService := client.NewDOPC(venueProvider)
-
-	// Create a new API handler and pass the DOPC service to it.
-	handler := api.NewHandler(dopcService)
-
-	// Create a new router using the chi router package.
-	r := chi.NewRouter()
+	// Create the structured JSON logger and make it the default for packages without a request context.
+	logger, err := logging.New(config.Logging.Level, os.Stdout)
+	if err != nil {
+		log.Fatalf("failed to create logger: %v", err)
+	}
+	slog.SetDefault(logger)
 
-	// Add middleware for logging and recovering from panics.
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	// Install the OpenTelemetry tracer provider with the configured exporter.
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		logger.Error("failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
-	// Define an HTTP GET route for fetching delivery order prices.
-	r.Get("/api/v1/delivery-order-price", handler.GetDeliveryOrderPrice)
+	// Build the router and its dependencies, then start the background workers.
+	application, err := app.New(config, logger)
+	if err != nil {
+		logger.Error("failed to build the service", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	application.Start()
 
 	// Create an HTTP server instance with the specified address and handler.
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%d", config.Server.Port),
-		Handler: r,
+		Handler: application.Handler,
 	}
 
 	// Log the server start message.
-	log.Printf("Starting server on %d", config.Server.Port)
+	logger.Info("starting server", slog.Int("port", config.Server.Port))
+
+	// Start the HTTP server in the background so that shutdown signals can be handled.
+	serverErr := make(chan error, 2)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	// Start the gRPC server on its own port, if enabled.
+	if application.GRPCServer != nil {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.GRPC.Port))
+		if err != nil {
+			logger.Error("could not listen", slog.Int("port", config.GRPC.Port), slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		logger.Info("starting gRPC server", slog.Int("port", config.GRPC.Port))
+		go func() {
+			if err := application.GRPCServer.Serve(lis); err != nil {
+				serverErr <- fmt.Errorf("gRPC server on port %d: %w", config.GRPC.Port, err)
+			}
+		}()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		logger.Error("could not listen", slog.Int("port", config.Server.Port), slog.String("error", err.Error()))
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+
+	// Report not ready first so the orchestrator stops routing traffic, then drain in-flight requests.
+	logger.Info("shutting down server")
+	application.SetShuttingDown()
+
+	shutdownTimeout := config.Server.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("graceful shutdown failed", slog.String("error", err.Error()))
+	}
+
+	// Let the gRPC calls in flight finish, cancelling them if the shutdown timeout expires first.
+	if application.GRPCServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			application.GRPCServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			logger.Error("graceful gRPC shutdown failed", slog.String("error", shutdownCtx.Err().Error()))
+			application.GRPCServer.Stop()
+		}
+	}
+
+	// Stop the background workers and wait for the venue refreshes and snapshot writes in flight.
+	if err := application.Stop(shutdownCtx); err != nil {
+		logger.Warn("failed to stop background workers", slog.String("error", err.Error()))
+	}
 
-	// Start the HTTP server and handle any errors.
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Could not listen on %d: %v\n", config.Server.Port, err)
+	// Flush the spans that are still buffered in the exporter.
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", slog.String("error", err.Error()))
 	}
 }
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ExclusionStore defines the interface for listing and updating the excluded areas.
+type ExclusionStore interface {
+	List() []exclusions.Exclusion
+	Get(id string) (exclusions.Exclusion, bool)
+	Put(exclusions.Exclusion) error
+	Delete(id string) bool
+}
+
+// ExclusionHandler is the HTTP handler of the administrative exclusion endpoints.
+type ExclusionHandler struct {
+	store ExclusionStore
+}
+
+// NewExclusionHandler creates a new ExclusionHandler instance with the provided ExclusionStore.
+func NewExclusionHandler(store ExclusionStore) *ExclusionHandler {
+	return &ExclusionHandler{store: store}
+}
+
+// ListExclusions handles HTTP GET requests listing the excluded areas applying to the venues of
+// the caller: the exclusions of every venue and those of the venues it may see.
+func (h *ExclusionHandler) ListExclusions(w http.ResponseWriter, r *http.Request) {
+	list := []exclusions.Exclusion{}
+	for _, exclusion := range h.store.List() {
+		if exclusion.VenueSlug == "" || venueAllowed(r.Context(), exclusion.VenueSlug) {
+			list = append(list, exclusion)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// PutExclusion handles HTTP PUT requests creating or replacing the excluded area with the ID
+// of the path. The body is an exclusion; its id, if set, must match the path. Callers restricted
+// to some venues can only create and replace exclusions of those venues.
+func (h *ExclusionHandler) PutExclusion(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var exclusion exclusions.Exclusion
+	if err := json.NewDecoder(r.Body).Decode(&exclusion); err != nil {
+		http.Error(w, "Invalid exclusion body", http.StatusBadRequest)
+		return
+	}
+	if exclusion.ID != "" && exclusion.ID != id {
+		http.Error(w, "Exclusion id does not match the path", http.StatusBadRequest)
+		return
+	}
+	exclusion.ID = id
+	if !checkExclusionAllowed(w, r, exclusion.VenueSlug) {
+		return
+	}
+	if previous, ok := h.store.Get(id); ok && !checkExclusionAllowed(w, r, previous.VenueSlug) {
+		return
+	}
+
+	if err := h.store.Put(exclusion); err != nil {
+		if errors.Is(err, exclusions.ErrInvalidExclusion) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logging.FromContext(r.Context()).InfoContext(r.Context(), "exclusion updated",
+		slog.String("exclusion_id", id),
+		slog.String("venue_slug", exclusion.VenueSlug),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteExclusion handles HTTP DELETE requests removing the excluded area with the ID of the path.
+func (h *ExclusionHandler) DeleteExclusion(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if exclusion, ok := h.store.Get(id); ok && !checkExclusionAllowed(w, r, exclusion.VenueSlug) {
+		return
+	}
+	if !h.store.Delete(id) {
+		http.Error(w, "Exclusion not found", http.StatusNotFound)
+		return
+	}
+	logging.FromContext(r.Context()).InfoContext(r.Context(), "exclusion deleted", slog.String("exclusion_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// exclusionAllowed reports whether the caller carried by ctx may see and change an exclusion of
+// the venue. Exclusions of every venue (empty venueSlug) are reserved to callers that are not
+// restricted to some venues.
+func exclusionAllowed(ctx context.Context, venueSlug string) bool {
+	if venueSlug != "" {
+		return venueAllowed(ctx, venueSlug)
+	}
+	identity, ok := auth.IdentityFromContext(ctx)
+	return !ok || len(identity.VenuePatterns) == 0
+}
+
+// checkExclusionAllowed responds 403 and returns false if the caller may not change an exclusion
+// of the venue.
+func checkExclusionAllowed(w http.ResponseWriter, r *http.Request, venueSlug string) bool {
+	if !exclusionAllowed(r.Context(), venueSlug) {
+		http.Error(w, "Venue not allowed for this API key", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/exclusions"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// newExclusionRouter routes the exclusion endpoints to a handler backed by store.
+func newExclusionRouter(store ExclusionStore) http.Handler {
+	handler := NewExclusionHandler(store)
+	r := chi.NewRouter()
+	r.Get("/admin/v1/exclusions", handler.ListExclusions)
+	r.Put("/admin/v1/exclusions/{id}", handler.PutExclusion)
+	r.Delete("/admin/v1/exclusions/{id}", handler.DeleteExclusion)
+	return r
+}
+
+const airportGeometry = `{"type": "Polygon", "coordinates": [[[24.9, 60.3], [25.0, 60.3], [25.0, 60.35], [24.9, 60.35], [24.9, 60.3]]]}`
+
+func TestExclusionHandler(t *testing.T) {
+	store := exclusions.NewStore()
+	router := newExclusionRouter(store)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve(http.MethodPut, "/admin/v1/exclusions/hel", `{"reason": "airport", "geometry": `+airportGeometry+`}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Error(t, store.Check("any-venue", 60.32, 24.96))
+
+	rec = serve(http.MethodGet, "/admin/v1/exclusions", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"id": "hel", "reason": "airport", "geometry": `+airportGeometry+`}]`, rec.Body.String())
+
+	rec = serve(http.MethodDelete, "/admin/v1/exclusions/hel", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.NoError(t, store.Check("any-venue", 60.32, 24.96))
+
+	rec = serve(http.MethodDelete, "/admin/v1/exclusions/hel", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExclusionHandler_PutInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"InvalidJSON", `{"reason":`},
+		{"MismatchedID", `{"id": "other", "geometry": ` + airportGeometry + `}`},
+		{"InvalidGeometry", `{"geometry": {"type": "Point", "coordinates": [24.9, 60.3]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := exclusions.NewStore()
+			rec := httptest.NewRecorder()
+			newExclusionRouter(store).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/v1/exclusions/hel", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Empty(t, store.List())
+		})
+	}
+}
+
+func TestExclusionHandler_VenueScope(t *testing.T) {
+	store := exclusions.NewStore()
+	for _, exclusion := range []exclusions.Exclusion{
+		{ID: "global", Geometry: []byte(airportGeometry)},
+		{ID: "acme", VenueSlug: "acme-helsinki", Geometry: []byte(airportGeometry)},
+		{ID: "other", VenueSlug: "other-venue", Geometry: []byte(airportGeometry)},
+	} {
+		if err := store.Put(exclusion); err != nil {
+			t.Fatalf("failed to store exclusion: %v", err)
+		}
+	}
+	router := newExclusionRouter(store)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "acme-key", VenuePatterns: []string{"acme-*"}}))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Exclusions of other venues and of every venue cannot be created, replaced or removed.
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/admin/v1/exclusions/new", `{"venue_slug": "other-venue", "geometry": `+airportGeometry+`}`).Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/admin/v1/exclusions/new", `{"geometry": `+airportGeometry+`}`).Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/admin/v1/exclusions/other", `{"venue_slug": "acme-helsinki", "geometry": `+airportGeometry+`}`).Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, "/admin/v1/exclusions/other", "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, "/admin/v1/exclusions/global", "").Code)
+	assert.Len(t, store.List(), 3)
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodPut, "/admin/v1/exclusions/acme", `{"venue_slug": "acme-helsinki", "reason": "roadworks", "geometry": `+airportGeometry+`}`).Code)
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/admin/v1/exclusions/acme", "").Code)
+
+	// The list holds the exclusions applying to the venues in scope.
+	var list []exclusions.Exclusion
+	assert.NoError(t, json.Unmarshal(serve(http.MethodGet, "/admin/v1/exclusions", "").Body.Bytes(), &list))
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, "global", list[0].ID)
+	}
+}
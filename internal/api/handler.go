@@ -1,13 +1,33 @@
 package api
 
 import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/client"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/logging"
 	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
+	"backend-wolt-go/internal/service"
+	"backend-wolt-go/internal/utils"
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// tracer creates the spans for the HTTP handlers.
+var tracer = otel.Tracer("backend-wolt-go/internal/api")
+
 // DOPCService defines the interface for a service that calculates delivery fees.
 type DOPCService interface {
 	// CalculateDeliveryFee calculates the delivery fee based on order information.
@@ -15,6 +35,21 @@ type DOPCService interface {
 	CalculateDeliveryFee(context.Context, *models.OrderInfo) (models.PriceResponse, error)
 }
 
+// errorResponse is the body of errors that clients can act upon.
+type errorResponse struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason,omitempty"` // Machine readable reason code.
+
+	NextAvailableAt *time.Time `json:"next_available_at,omitempty"` // Next time the venue delivers, if known.
+}
+
+// writeJSON writes the value as a JSON response with the status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
 // Handler is the HTTP handler for delivery order price calculation.
 type Handler struct {
 	service DOPCService
@@ -29,86 +64,199 @@ func NewHandler(service DOPCService) *Handler {
 // It validates query parameters, constructs the order information, calls the service,
 // and returns the delivery fee as a JSON response.
 func (h *Handler) GetDeliveryOrderPrice(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "Handler.GetDeliveryOrderPrice")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	orderInfo, ok := parseOrderInfo(w, r)
+	if !ok {
+		return
+	}
+
+	// Extract and validate the optional scheduled_for parameter of scheduled orders. delivery_time
+	// is accepted as an alias.
+	for _, name := range []string{"scheduled_for", "delivery_time"} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		deliveryTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Invalid delivery time, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		if deliveryTime.Before(time.Now()) {
+			http.Error(w, "Delivery time must not be in the past", http.StatusBadRequest)
+			return
+		}
+		if !orderInfo.DeliveryTime.IsZero() && !orderInfo.DeliveryTime.Equal(deliveryTime) {
+			http.Error(w, "Parameters scheduled_for and delivery_time differ", http.StatusBadRequest)
+			return
+		}
+		orderInfo.DeliveryTime = deliveryTime
+	}
+
+	span.SetAttributes(attribute.String("venue.slug", orderInfo.Slug), attribute.Int("order.cart_value", orderInfo.CartValue))
+
+	// Call the service to calculate the delivery fee.
+	response, err := h.service.CalculateDeliveryFee(r.Context(), orderInfo)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeServiceError(w, r, err)
+		return
+	}
+
+	// Set the response content type to JSON and encode the response.
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseOrderInfo validates the venue and order query parameters shared by the pricing endpoints.
+// It writes the error response and returns false when they are invalid.
+func parseOrderInfo(w http.ResponseWriter, r *http.Request) (*models.OrderInfo, bool) {
 	// Extract and validate the venue_slug parameter.
 	venueSlug := r.URL.Query().Get("venue_slug")
 	if venueSlug == "" {
 		http.Error(w, "Missing required parameter: venue_slug", http.StatusBadRequest)
-		return
+		return nil, false
+	}
+
+	// Reject venues outside the scope of the authenticated tenant, if any.
+	if !checkVenueAllowed(w, r, venueSlug) {
+		return nil, false
 	}
 
 	// Extract and validate the user_lat parameter.
 	latStr := r.URL.Query().Get("user_lat")
 	if latStr == "" {
 		http.Error(w, "Missing required parameter: user_lat", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	lat, err := strconv.ParseFloat(latStr, 64)
-	if err != nil {
+	if err != nil || math.IsNaN(lat) {
 		http.Error(w, "Invalid user latitude", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	if lat < -90 || lat > 90 {
 		http.Error(w, "Latitude must be between -90 and 90", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	// Extract and validate the user_lon parameter.
 	lonStr := r.URL.Query().Get("user_lon")
 	if lonStr == "" {
 		http.Error(w, "Missing required parameter: user_lon", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	lon, err := strconv.ParseFloat(lonStr, 64)
-	if err != nil {
+	if err != nil || math.IsNaN(lon) {
 		http.Error(w, "Invalid user longitude", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	if lon < -180 || lon > 180 {
 		http.Error(w, "Longitude must be between -180 and 180", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	// Extract and validate the cart_value parameter.
 	cartValueStr := r.URL.Query().Get("cart_value")
 	if cartValueStr == "" {
 		http.Error(w, "Missing required parameter: cart_value", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	cartValue, err := strconv.Atoi(cartValueStr)
 	if err != nil {
 		http.Error(w, "Invalid cart value", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	if cartValue <= 0 {
 		http.Error(w, "Cart value must be a positive integer", http.StatusBadRequest)
-		return
+		return nil, false
 	}
+	// Cap the cart value like the gRPC API, so that the total price cannot overflow.
+	if cartValue > math.MaxInt32 {
+		http.Error(w, "Invalid cart value", http.StatusBadRequest)
+		return nil, false
+	}
+
+	// Create an OrderInfo struct with the validated parameters and the optional courier_mode.
+	return &models.OrderInfo{
+		Slug:        venueSlug,
+		Lat:         lat,
+		Lon:         lon,
+		CartValue:   cartValue,
+		CourierMode: r.URL.Query().Get("courier_mode"),
+	}, true
+}
 
-	// Create an OrderInfo struct with the validated parameters.
-	orderInfo := &models.OrderInfo{
-		Slug:      venueSlug,
-		Lat:       lat,
-		Lon:       lon,
-		CartValue: cartValue,
+// venueAllowed reports whether the authenticated caller may access the venue. Every venue is
+// allowed to unauthenticated callers and to identities without venue patterns.
+func venueAllowed(ctx context.Context, venueSlug string) bool {
+	identity, ok := auth.IdentityFromContext(ctx)
+	return !ok || identity.AllowsVenue(venueSlug)
+}
+
+// checkVenueAllowed answers 403 Forbidden and returns false if the caller may not access the venue.
+func checkVenueAllowed(w http.ResponseWriter, r *http.Request, venueSlug string) bool {
+	if !venueAllowed(r.Context(), venueSlug) {
+		http.Error(w, "Venue not allowed for this API key", http.StatusForbidden)
+		return false
 	}
+	return true
+}
 
-	// Call the service to calculate the delivery fee.
-	response, err := h.service.CalculateDeliveryFee(r.Context(), orderInfo)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+// writeServiceError writes the response of an error of the pricing service: the status code,
+// and the reason code of the errors clients can act upon. Errors of the venue data and unexpected
+// errors get a stable message, and the error itself is only logged, since it may describe internals.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var limitErr *ratelimit.LimitExceededError
+	if errors.As(err, &limitErr) {
+		ratelimit.SetRetryAfter(w, limitErr.RetryAfter)
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
 		return
 	}
-
-	// Set the response content type to JSON and encode the response.
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	var notServiceableErr *exclusions.NotServiceableError
+	if errors.As(err, &notServiceableErr) {
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: err.Error(), Reason: notServiceableErr.Reason})
+		return
+	}
+	var modeErr *couriers.ModeUnavailableError
+	if errors.As(err, &modeErr) {
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: err.Error(), Reason: couriers.ReasonModeUnavailable})
+		return
+	}
+	var notDeliveringErr *availability.NotDeliveringError
+	if errors.As(err, &notDeliveringErr) {
+		response := errorResponse{Error: err.Error(), Reason: notDeliveringErr.Reason}
+		if !notDeliveringErr.NextAvailableAt.IsZero() {
+			response.NextAvailableAt = &notDeliveringErr.NextAvailableAt
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, response)
+		return
+	}
+	if errors.Is(err, utils.ErrDeliveryNotPossible) {
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: utils.ErrDeliveryNotPossible.Error(), Reason: utils.ReasonDistanceTooLong})
 		return
 	}
-}
\ No newline at end of file
+
+	logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to price order", slog.String("error", err.Error()))
+	switch {
+	case errors.Is(err, service.ErrUpstreamTimeout):
+		http.Error(w, "Venue API timed out", http.StatusGatewayTimeout)
+	case errors.Is(err, service.ErrUpstream), errors.Is(err, client.ErrInvalidVenueLocation):
+		http.Error(w, "Venue API unavailable", http.StatusBadGateway)
+	case errors.Is(err, service.ErrVenueNotFound):
+		http.Error(w, "Venue not found", http.StatusNotFound)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
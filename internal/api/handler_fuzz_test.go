@@ -0,0 +1,67 @@
+package api
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// recordingDOPCService records the order it was asked to price.
+type recordingDOPCService struct {
+	order *models.OrderInfo
+}
+
+func (s *recordingDOPCService) CalculateDeliveryFee(_ context.Context, orderInfo *models.OrderInfo) (models.PriceResponse, error) {
+	s.order = orderInfo
+	return models.PriceResponse{CartValue: orderInfo.CartValue}, nil
+}
+
+// FuzzGetDeliveryOrderPrice checks that the query parsing only lets valid orders reach the
+// service and answers everything else with 400. The seed corpus is under testdata/fuzz.
+func FuzzGetDeliveryOrderPrice(f *testing.F) {
+	f.Add("home-assignment-venue-helsinki", "60.17094", "24.93087", "1000")
+	f.Add("venue", "-90", "180", "1")
+	f.Add("venue", "NaN", "24.93", "1000")
+	f.Add("venue", "60.17", "+Inf", "1000")
+	f.Add("venue", "1e2", "0x1p-2", "0")
+	f.Add("", "60.17", "24.93", "-5")
+
+	f.Fuzz(func(t *testing.T, slug, lat, lon, cartValue string) {
+		service := &recordingDOPCService{}
+		query := url.Values{"venue_slug": {slug}, "user_lat": {lat}, "user_lon": {lon}, "cart_value": {cartValue}}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?"+query.Encode(), nil)
+		rec := httptest.NewRecorder()
+
+		NewHandler(service).GetDeliveryOrderPrice(rec, req)
+
+		switch rec.Code {
+		case http.StatusOK:
+			order := service.order
+			if order == nil {
+				t.Fatal("answered 200 without calling the service")
+			}
+			if order.Slug != slug || order.Slug == "" {
+				t.Fatalf("slug %q reached the service as %q", slug, order.Slug)
+			}
+			if math.IsNaN(order.Lat) || order.Lat < -90 || order.Lat > 90 {
+				t.Fatalf("invalid latitude %q reached the service as %v", lat, order.Lat)
+			}
+			if math.IsNaN(order.Lon) || order.Lon < -180 || order.Lon > 180 {
+				t.Fatalf("invalid longitude %q reached the service as %v", lon, order.Lon)
+			}
+			if order.CartValue <= 0 || order.CartValue > math.MaxInt32 {
+				t.Fatalf("invalid cart value %q reached the service as %d", cartValue, order.CartValue)
+			}
+		case http.StatusBadRequest:
+			if service.order != nil {
+				t.Fatalf("answered 400 after calling the service with %+v", service.order)
+			}
+		default:
+			t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
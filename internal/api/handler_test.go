@@ -2,14 +2,24 @@ package api
 
 import (
 	
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/client"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/exclusions"
 	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
+	"backend-wolt-go/internal/service"
+	"backend-wolt-go/internal/utils"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -116,6 +126,36 @@ func TestGetDeliveryOrderPrice_BadRequest(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantBody:   "Cart value must be a positive integer",
 		},
+		{
+			name:       "Cart_value above the int32 range",
+			params:     map[string]string{"venue_slug": "abc", "user_lat": "60.1699", "user_lon": "24.9384", "cart_value": "2147483648"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid cart value",
+		},
+		{
+			name:       "Invalid delivery_time",
+			params:     map[string]string{"venue_slug": "abc", "user_lat": "60.1699", "user_lon": "24.9384", "cart_value": "1500", "delivery_time": "tomorrow"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid delivery time",
+		},
+		{
+			name:       "Past delivery_time",
+			params:     map[string]string{"venue_slug": "abc", "user_lat": "60.1699", "user_lon": "24.9384", "cart_value": "1500", "delivery_time": "2020-01-01T12:00:00Z"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Delivery time must not be in the past",
+		},
+		{
+			name:       "Invalid scheduled_for",
+			params:     map[string]string{"venue_slug": "abc", "user_lat": "60.1699", "user_lon": "24.9384", "cart_value": "1500", "scheduled_for": "2030-01-07"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid delivery time",
+		},
+		{
+			name:       "Conflicting scheduled_for and delivery_time",
+			params:     map[string]string{"venue_slug": "abc", "user_lat": "60.1699", "user_lon": "24.9384", "cart_value": "1500", "scheduled_for": "2030-01-07T12:00:00Z", "delivery_time": "2030-01-07T13:00:00Z"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Parameters scheduled_for and delivery_time differ",
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,14 +182,9 @@ func TestGetDeliveryOrderPrice_Success(t *testing.T) {
 		TotalPrice:          2350,
 		SmallOrderSurcharge: 0,
 		CartValue:           2000,
-		Delivery: struct {
-			Fee      int `json:"fee"`
-			Distance int `json:"distance"`
-		}{
-			Fee:      350,
-			Distance: 987,
-		},
 	}
+	expectedPriceResponse.Delivery.Fee = 350
+	expectedPriceResponse.Delivery.Distance = 987
 
 	// Set up the mock to expect certain input and return `expectedPriceResponse`
 	service.On(
@@ -215,10 +250,172 @@ func TestGetDeliveryOrderPrice_ServiceError(t *testing.T) {
 
 	handler.GetDeliveryOrderPrice(rec, req)
 
-	// Expect 500 status for service errors
+	// Expect 500 status for service errors, without leaking the error
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Contains(t, rec.Body.String(), serviceErr.Error())
+	assert.Equal(t, "Internal server error\n", rec.Body.String())
 
 	// Verify the mock was called
 	service.AssertExpectations(t)
 }
+
+// ------------------------------
+// 5b. Test venue data error scenarios
+// ------------------------------
+func TestGetDeliveryOrderPrice_VenueErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"UnknownVenue", fmt.Errorf("failed to get static data: venue API returned 404 Not Found: %w", service.ErrVenueNotFound), http.StatusNotFound, "Venue not found\n"},
+		{"UpstreamFailure", fmt.Errorf("failed to get static data: %w: unexpected status code: 503 Service Unavailable", service.ErrUpstream), http.StatusBadGateway, "Venue API unavailable\n"},
+		{"UpstreamTimeout", fmt.Errorf("failed to get dynamic data: %w", service.ErrUpstreamTimeout), http.StatusGatewayTimeout, "Venue API timed out\n"},
+		{"InvalidVenueLocation", client.ErrInvalidVenueLocation, http.StatusBadGateway, "Venue API unavailable\n"},
+		{"DeliveryNotPossible", utils.ErrDeliveryNotPossible, http.StatusUnprocessableEntity, `{"error":"delivery is not possible, distance too long","reason":"distance_too_long"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockDOPCService)
+			service.On("CalculateDeliveryFee", mock.Anything, mock.AnythingOfType("*models.OrderInfo")).Return(models.PriceResponse{}, tt.err)
+
+			rec := httptest.NewRecorder()
+			NewHandler(service).GetDeliveryOrderPrice(rec, buildRequest(map[string]string{
+				"venue_slug": "venue-slug",
+				"user_lat":   "60.1699",
+				"user_lon":   "24.9384",
+				"cart_value": "1500",
+			}))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+// ------------------------------
+// 6. Test upstream rate limit scenario
+// ------------------------------
+func TestGetDeliveryOrderPrice_RateLimited(t *testing.T) {
+	service := new(mockDOPCService)
+	handler := NewHandler(service)
+
+	limitErr := fmt.Errorf("upstream calls limited: %w", &ratelimit.LimitExceededError{Key: "venue:venue-slug", RetryAfter: 1500 * time.Millisecond})
+	service.On("CalculateDeliveryFee", mock.Anything, mock.AnythingOfType("*models.OrderInfo")).Return(models.PriceResponse{}, limitErr)
+
+	rec := httptest.NewRecorder()
+	req := buildRequest(map[string]string{
+		"venue_slug": "venue-slug",
+		"user_lat":   "60.1699",
+		"user_lon":   "24.9384",
+		"cart_value": "1500",
+	})
+
+	handler.GetDeliveryOrderPrice(rec, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+// ------------------------------
+// 7. Test tenant venue scoping
+// ------------------------------
+func TestGetDeliveryOrderPrice_VenueNotAllowed(t *testing.T) {
+	service := new(mockDOPCService)
+	handler := NewHandler(service)
+
+	req := buildRequest(map[string]string{
+		"venue_slug": "other-venue",
+		"user_lat":   "60.1699",
+		"user_lon":   "24.9384",
+		"cart_value": "1500",
+	})
+	req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "key-1", VenuePatterns: []string{"acme-*"}}))
+	rec := httptest.NewRecorder()
+
+	handler.GetDeliveryOrderPrice(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	service.AssertNotCalled(t, "CalculateDeliveryFee", mock.Anything, mock.Anything)
+}
+
+// ------------------------------
+// 8. Test excluded address scenario
+// ------------------------------
+func TestGetDeliveryOrderPrice_NotServiceable(t *testing.T) {
+	service := new(mockDOPCService)
+	handler := NewHandler(service)
+
+	service.On("CalculateDeliveryFee", mock.Anything, mock.AnythingOfType("*models.OrderInfo")).
+		Return(models.PriceResponse{}, &exclusions.NotServiceableError{Reason: "airport", ExclusionID: "hel"})
+
+	rec := httptest.NewRecorder()
+	req := buildRequest(map[string]string{
+		"venue_slug": "venue-slug",
+		"user_lat":   "60.3172",
+		"user_lon":   "24.9633",
+		"cart_value": "1500",
+	})
+
+	handler.GetDeliveryOrderPrice(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error": "address not serviceable: airport", "reason": "airport"}`, rec.Body.String())
+}
+
+// ------------------------------
+// 9. Test venue not delivering scenario
+// ------------------------------
+func TestGetDeliveryOrderPrice_NotDelivering(t *testing.T) {
+	service := new(mockDOPCService)
+	handler := NewHandler(service)
+
+	deliveryTime := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second) // UTC, since buildRequest does not escape "+".
+	nextAvailableAt := time.Date(2030, 1, 7, 10, 0, 0, 0, time.FixedZone("EET", 2*60*60))
+	service.On("CalculateDeliveryFee", mock.Anything, mock.MatchedBy(func(orderInfo *models.OrderInfo) bool {
+		return orderInfo.DeliveryTime.Equal(deliveryTime)
+	})).Return(models.PriceResponse{}, &availability.NotDeliveringError{Reason: availability.ReasonClosed, NextAvailableAt: nextAvailableAt})
+
+	rec := httptest.NewRecorder()
+	req := buildRequest(map[string]string{
+		"venue_slug":    "venue-slug",
+		"user_lat":      "60.1699",
+		"user_lon":      "24.9384",
+		"cart_value":    "1500",
+		"delivery_time": deliveryTime.Format(time.RFC3339),
+	})
+
+	handler.GetDeliveryOrderPrice(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"error": "venue is not currently delivering: venue_closed", "reason": "venue_closed", "next_available_at": "2030-01-07T10:00:00+02:00"}`, rec.Body.String())
+	service.AssertExpectations(t)
+}
+
+// ------------------------------
+// 10. Test courier mode scenario
+// ------------------------------
+func TestGetDeliveryOrderPrice_CourierModeUnavailable(t *testing.T) {
+	service := new(mockDOPCService)
+	handler := NewHandler(service)
+
+	service.On("CalculateDeliveryFee", mock.Anything, mock.MatchedBy(func(orderInfo *models.OrderInfo) bool {
+		return orderInfo.CourierMode == "car"
+	})).Return(models.PriceResponse{}, &couriers.ModeUnavailableError{Mode: "car"})
+
+	rec := httptest.NewRecorder()
+	req := buildRequest(map[string]string{
+		"venue_slug":   "venue-slug",
+		"user_lat":     "60.1699",
+		"user_lon":     "24.9384",
+		"cart_value":   "1500",
+		"courier_mode": "car",
+	})
+
+	handler.GetDeliveryOrderPrice(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"error": "courier mode \"car\" is not available for this delivery", "reason": "courier_mode_unavailable"}`, rec.Body.String())
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,456 @@
+package api
+
+import (
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/health"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/overrides"
+	"backend-wolt-go/internal/quotelog"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/go-chi/chi/v5"
+)
+
+// OpenAPIVersion is the version of the HTTP API reported in the OpenAPI document.
+const OpenAPIVersion = "1.0.0"
+
+// schemaTypes lists the Go types encoded in the response and request bodies by the name of their
+// component schema.
+var schemaTypes = map[string]any{
+	"PriceResponse": models.PriceResponse{},
+	"SlotsResponse": slotsResponse{},
+	"ErrorResponse": errorResponse{},
+	"HealthReport":  health.Report{},
+	"Exclusion":     exclusions.Exclusion{},
+	"Override":      overrides.Override{},
+	"AuditEntry":    overrides.AuditEntry{},
+	"QuoteEvent":    quotelog.Event{},
+}
+
+// OpenAPI returns the OpenAPI 3 document describing every HTTP route of the service. The schemas
+// of the bodies are generated from the Go types the handlers encode, so that they follow the code.
+func OpenAPI() (*openapi3.T, error) {
+	schemas := openapi3.Schemas{}
+	generator := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(customizeSchema))
+	for name, value := range schemaTypes {
+		ref, err := generator.NewSchemaRefForValue(value, schemas)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate schema %s: %w", name, err)
+		}
+		schemas[name] = openapi3.NewSchemaRef("", ref.Value)
+	}
+	schemas["PriceResponse"].Value.Description = "Price of an order, with the breakdown of the delivery fee."
+	schemas["ErrorResponse"].Value.Description = "Error clients can act upon, with a machine readable reason code."
+	geometry := openapi3.NewObjectSchema()
+	geometry.Description = "GeoJSON Polygon or MultiPolygon geometry."
+	schemas["Exclusion"].Value.Properties["geometry"] = openapi3.NewSchemaRef("", geometry)
+	schemas["ExclusionBody"] = bodySchema(schemas["Exclusion"], "Body of the request; id defaults to the path parameter and reason to excluded_area.", "id", "reason")
+	schemas["OverrideBody"] = bodySchema(schemas["Override"], "Body of the request; venue_slug defaults to the path parameter.", "venue_slug")
+
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Delivery Order Price Calculator",
+			Description: "Calculates the total price and price breakdown of delivery orders.",
+			Version:     OpenAPIVersion,
+		},
+		Components: &openapi3.Components{
+			Schemas: schemas,
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"apiKey": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().
+					WithType("apiKey").WithIn("header").WithName("X-API-Key").
+					WithDescription("API key, in the header configured in auth.header, when auth.enabled is set.")},
+				"bearer": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme().
+					WithDescription("JWT of the identity provider when auth.jwt.enabled is set.")},
+			},
+		},
+		Paths: openapi3.NewPaths(),
+	}
+
+	priceParams := orderParameters()
+	priceParams = append(priceParams,
+		queryParameter("scheduled_for", "Delivery time of a scheduled order, in the future. The order is delivered as soon as possible when unset.", openapi3.NewDateTimeSchema()),
+		deprecated(queryParameter("delivery_time", "Alias of scheduled_for.", openapi3.NewDateTimeSchema())),
+	)
+	doc.AddOperation("/api/v1/delivery-order-price", http.MethodGet, &openapi3.Operation{
+		OperationID: "getDeliveryOrderPrice",
+		Summary:     "Calculate the price of a delivery order",
+		Tags:        []string{"pricing"},
+		Parameters:  priceParams,
+		Security:    authenticated(),
+		Responses:   pricingResponses("The price of the order.", "PriceResponse"),
+	})
+
+	slotParams := orderParameters()
+	slotParams = append(slotParams,
+		queryParameter("from", "Start of the listed slots; now when unset or in the past.", openapi3.NewDateTimeSchema()),
+	)
+	doc.AddOperation("/api/v1/delivery-slots", http.MethodGet, &openapi3.Operation{
+		OperationID: "getDeliverySlots",
+		Summary:     "List the upcoming delivery slots of a venue with their prices",
+		Tags:        []string{"pricing"},
+		Parameters:  slotParams,
+		Security:    authenticated(),
+		Responses:   pricingResponses("The price of every slot at which the venue delivers.", "SlotsResponse"),
+	})
+
+	doc.AddOperation("/healthz", http.MethodGet, &openapi3.Operation{
+		OperationID: "getLiveness",
+		Summary:     "Liveness probe",
+		Tags:        []string{"operations"},
+		Responses:   openapi3.NewResponses(withJSON(http.StatusOK, "The process is alive.", "HealthReport")),
+	})
+	doc.AddOperation("/readyz", http.MethodGet, &openapi3.Operation{
+		OperationID: "getReadiness",
+		Summary:     "Readiness probe",
+		Tags:        []string{"operations"},
+		Responses: openapi3.NewResponses(
+			withJSON(http.StatusOK, "Every component is up.", "HealthReport"),
+			withJSON(http.StatusServiceUnavailable, "A component is down or the server is shutting down.", "HealthReport"),
+		),
+	})
+	doc.AddOperation("/metrics", http.MethodGet, &openapi3.Operation{
+		OperationID: "getMetrics",
+		Summary:     "Prometheus metrics",
+		Tags:        []string{"operations"},
+		Responses:   openapi3.NewResponses(withText(http.StatusOK, "Metrics in the Prometheus text format.")),
+	})
+	doc.AddOperation("/openapi.json", http.MethodGet, &openapi3.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "This OpenAPI document",
+		Tags:        []string{"operations"},
+		Responses: openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription("The OpenAPI document.").
+			WithContent(openapi3.NewContentWithJSONSchema(openapi3.NewObjectSchema()))})),
+	})
+	doc.AddOperation("/docs", http.MethodGet, &openapi3.Operation{
+		OperationID: "getDocs",
+		Summary:     "Interactive documentation of this OpenAPI document, when openapi.docs_ui is set",
+		Tags:        []string{"operations"},
+		Responses: openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription("The documentation page.").
+			WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/html"}))})),
+	})
+	assetFile := openapi3.NewPathParameter("file").WithDescription("Name of the Swagger UI file, swagger-ui.css or swagger-ui-bundle.js.").
+		WithSchema(openapi3.NewStringSchema())
+	doc.AddOperation("/docs/{file}", http.MethodGet, withParameters(&openapi3.Operation{
+		OperationID: "getDocsAsset",
+		Summary:     "Swagger UI file of the documentation page, when openapi.docs_ui is set",
+		Tags:        []string{"operations"},
+		Responses: openapi3.NewResponses(
+			openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+				WithDescription("The file.").
+				WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/css", "text/javascript"}))}),
+			withText(http.StatusNotFound, "The file is not served."),
+		),
+	}, assetFile))
+
+	exclusionID := openapi3.NewPathParameter("id").WithDescription("ID of the excluded area.").WithSchema(openapi3.NewStringSchema())
+	doc.AddOperation("/admin/v1/exclusions", http.MethodGet, adminOperation("listExclusions", "List the excluded areas",
+		withJSONArray(http.StatusOK, "The excluded areas.", "Exclusion")))
+	doc.AddOperation("/admin/v1/exclusions/{id}", http.MethodPut, withBody(adminOperation("putExclusion", "Create or replace an excluded area",
+		withEmpty(http.StatusNoContent, "The area was stored."),
+		withText(http.StatusBadRequest, "The body is not a valid exclusion."),
+	), "ExclusionBody", exclusionID))
+	doc.AddOperation("/admin/v1/exclusions/{id}", http.MethodDelete, withParameters(adminOperation("deleteExclusion", "Remove an excluded area",
+		withEmpty(http.StatusNoContent, "The area was removed."),
+		withText(http.StatusNotFound, "No area has the ID."),
+	), exclusionID))
+
+	venueSlug := openapi3.NewPathParameter("venue_slug").WithDescription("Slug of the venue.").WithSchema(openapi3.NewStringSchema())
+	doc.AddOperation("/admin/v1/overrides", http.MethodGet, adminOperation("listOverrides", "List the pricing overrides",
+		withJSONArray(http.StatusOK, "The pricing overrides, by venue slug.", "Override")))
+	doc.AddOperation("/admin/v1/overrides/{venue_slug}", http.MethodGet, withParameters(adminOperation("getOverride", "Get the pricing override of a venue",
+		withJSON(http.StatusOK, "The pricing override.", "Override"),
+		withText(http.StatusNotFound, "The venue has no override."),
+	), venueSlug))
+	doc.AddOperation("/admin/v1/overrides/{venue_slug}", http.MethodPut, withBody(adminOperation("putOverride", "Create or replace the pricing override of a venue",
+		withEmpty(http.StatusNoContent, "The override was stored."),
+		withText(http.StatusBadRequest, "The body is not a valid override."),
+	), "OverrideBody", venueSlug))
+	doc.AddOperation("/admin/v1/overrides/{venue_slug}", http.MethodDelete, withParameters(adminOperation("deleteOverride", "Remove the pricing override of a venue",
+		withEmpty(http.StatusNoContent, "The override was removed."),
+		withText(http.StatusNotFound, "The venue has no override."),
+	), venueSlug))
+	doc.AddOperation("/admin/v1/audit/overrides", http.MethodGet, adminOperation("listOverrideAudit", "List the changes of the pricing overrides",
+		withJSONArray(http.StatusOK, "The changes, oldest first.", "AuditEntry")))
+
+	doc.AddOperation("/admin/v1/quotes", http.MethodGet, withParameters(adminOperation("getQuote", "Look up recorded price calculations, when the quote log is enabled",
+		withJSONArray(http.StatusOK, "The calculations, oldest first.", "QuoteEvent"),
+		withText(http.StatusBadRequest, "The id parameter is missing."),
+		withText(http.StatusNotFound, "No calculation has the ID."),
+	), required(queryParameter("id", "Quote ID, or request ID of every calculation of a request.", openapi3.NewStringSchema()))))
+
+	// Resolve the references to the component schemas, so that the document can validate
+	// requests and responses as built.
+	if err := openapi3.NewLoader().ResolveRefsIn(doc, nil); err != nil {
+		return nil, fmt.Errorf("could not resolve OpenAPI references: %w", err)
+	}
+	return doc, nil
+}
+
+// customizeSchema marks the fields encoded without omitempty as required, and disallows
+// properties missing from the Go types so that responses cannot grow undocumented fields.
+func customizeSchema(_ string, t reflect.Type, _ reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) || schema.Properties == nil {
+		return nil
+	}
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		tag, ok := field.Tag.Lookup("json")
+		name, options, _ := strings.Cut(tag, ",")
+		if !ok || name == "" || name == "-" || strings.Contains(options, "omitempty") {
+			continue
+		}
+		schema.Required = append(schema.Required, name)
+	}
+	schema.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.Ptr(false)}
+	return nil
+}
+
+// bodySchema returns the schema of a request body creating the resource of the schema, in which
+// the properties with a default, such as the key taken from the path, are optional. Keys set in
+// the body must match the path.
+func bodySchema(resource *openapi3.SchemaRef, description string, optional ...string) *openapi3.SchemaRef {
+	schema := *resource.Value
+	schema.Description = description
+	schema.Required = nil
+	for _, name := range resource.Value.Required {
+		if !slices.Contains(optional, name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return openapi3.NewSchemaRef("", &schema)
+}
+
+// orderParameters returns the order parameters shared by the pricing endpoints.
+func orderParameters() openapi3.Parameters {
+	return openapi3.Parameters{
+		required(queryParameter("venue_slug", "Slug of the venue.", openapi3.NewStringSchema())),
+		required(queryParameter("user_lat", "Latitude of the delivery address.", openapi3.NewFloat64Schema().WithMin(-90).WithMax(90))),
+		required(queryParameter("user_lon", "Longitude of the delivery address.", openapi3.NewFloat64Schema().WithMin(-180).WithMax(180))),
+		required(queryParameter("cart_value", "Value of the cart in the smallest unit of the currency.", openapi3.NewIntegerSchema().WithMin(1).WithMax(math.MaxInt32))),
+		queryParameter("courier_mode", "Courier transport mode to price the delivery for; the cheapest available one when unset.", openapi3.NewStringSchema()),
+	}
+}
+
+// pricingResponses returns the responses of the pricing endpoints, the body of the successful
+// one being the schema named schema.
+func pricingResponses(description, schema string) *openapi3.Responses {
+	rateLimited := textResponse("The client or the venue is rate limited.")
+	rateLimited.Headers = openapi3.Headers{
+		"Retry-After": &openapi3.HeaderRef{Value: &openapi3.Header{Parameter: openapi3.Parameter{
+			Description: "Seconds until the next request is allowed.",
+			Schema:      openapi3.NewSchemaRef("", openapi3.NewIntegerSchema()),
+		}}},
+	}
+	return openapi3.NewResponses(
+		withJSON(http.StatusOK, description, schema),
+		withText(http.StatusBadRequest, "A parameter is missing or invalid."),
+		withText(http.StatusUnauthorized, "The API key or bearer token is missing or invalid."),
+		withText(http.StatusForbidden, "The token lacks the pricing:read scope, or the API key is not allowed for the venue."),
+		withJSON(http.StatusUnprocessableEntity, "The venue does not deliver the order, for the reason in the body, such as distance_too_long.", "ErrorResponse"),
+		openapi3.WithStatus(http.StatusTooManyRequests, &openapi3.ResponseRef{Value: rateLimited}),
+		withText(http.StatusNotFound, "The venue is unknown."),
+		withText(http.StatusInternalServerError, "The price could not be calculated."),
+		withText(http.StatusBadGateway, "The venue API failed or returned invalid venue data."),
+		withText(http.StatusServiceUnavailable, "The API keys could not be checked."),
+		withText(http.StatusGatewayTimeout, "The venue API timed out."),
+	)
+}
+
+// adminOperation returns an operation of the administrative endpoints with the responses.
+func adminOperation(id, summary string, responses ...openapi3.NewResponsesOption) *openapi3.Operation {
+	responses = append(responses,
+		withText(http.StatusUnauthorized, "The API key or bearer token is missing or invalid, or authentication is disabled."),
+		withText(http.StatusForbidden, "The caller lacks the pricing:admin scope."),
+	)
+	return &openapi3.Operation{
+		OperationID: id,
+		Summary:     summary,
+		Tags:        []string{"admin"},
+		Security:    adminAuthenticated(),
+		Responses:   openapi3.NewResponses(responses...),
+	}
+}
+
+// authenticated returns the security requirements of the pricing routes. They only apply when
+// authentication is enabled, whereas the administrative routes always require it.
+func authenticated() *openapi3.SecurityRequirements {
+	return &openapi3.SecurityRequirements{
+		openapi3.NewSecurityRequirement().Authenticate("apiKey"),
+		openapi3.NewSecurityRequirement().Authenticate("bearer"),
+		openapi3.NewSecurityRequirement(),
+	}
+}
+
+// adminAuthenticated returns the security requirements of the administrative routes, which
+// reject anonymous requests even when authentication is disabled.
+func adminAuthenticated() *openapi3.SecurityRequirements {
+	return &openapi3.SecurityRequirements{
+		openapi3.NewSecurityRequirement().Authenticate("apiKey"),
+		openapi3.NewSecurityRequirement().Authenticate("bearer"),
+	}
+}
+
+// queryParameter returns an optional query parameter.
+func queryParameter(name, description string, schema *openapi3.Schema) *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{Value: openapi3.NewQueryParameter(name).WithDescription(description).WithSchema(schema)}
+}
+
+// required marks the parameter as required.
+func required(parameter *openapi3.ParameterRef) *openapi3.ParameterRef {
+	parameter.Value.Required = true
+	return parameter
+}
+
+// deprecated marks the parameter as deprecated.
+func deprecated(parameter *openapi3.ParameterRef) *openapi3.ParameterRef {
+	parameter.Value.Deprecated = true
+	return parameter
+}
+
+// withParameters adds the path or query parameters to the operation.
+func withParameters(operation *openapi3.Operation, parameters ...any) *openapi3.Operation {
+	for _, parameter := range parameters {
+		switch parameter := parameter.(type) {
+		case *openapi3.Parameter:
+			operation.Parameters = append(operation.Parameters, &openapi3.ParameterRef{Value: parameter})
+		case *openapi3.ParameterRef:
+			operation.Parameters = append(operation.Parameters, parameter)
+		}
+	}
+	return operation
+}
+
+// withBody adds the required JSON body of the schema named schema and the path parameter to the
+// operation.
+func withBody(operation *openapi3.Operation, schema string, parameter *openapi3.Parameter) *openapi3.Operation {
+	operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+		WithRequired(true).
+		WithJSONSchemaRef(schemaRef(schema))}
+	return withParameters(operation, parameter)
+}
+
+// withJSON returns a response whose JSON body is the schema named schema.
+func withJSON(status int, description, schema string) openapi3.NewResponsesOption {
+	return openapi3.WithStatus(status, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithDescription(description).
+		WithContent(openapi3.NewContentWithJSONSchemaRef(schemaRef(schema)))})
+}
+
+// withJSONArray returns a response whose JSON body is an array of the schema named schema.
+func withJSONArray(status int, description, schema string) openapi3.NewResponsesOption {
+	array := openapi3.NewArraySchema()
+	array.Items = schemaRef(schema)
+	return openapi3.WithStatus(status, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithDescription(description).
+		WithContent(openapi3.NewContentWithJSONSchema(array))})
+}
+
+// withText returns a response whose body is a plain text message, as written by http.Error.
+func withText(status int, description string) openapi3.NewResponsesOption {
+	return openapi3.WithStatus(status, &openapi3.ResponseRef{Value: textResponse(description)})
+}
+
+// textResponse returns a response whose body is a plain text message.
+func textResponse(description string) *openapi3.Response {
+	return openapi3.NewResponse().
+		WithDescription(description).
+		WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/plain"}))
+}
+
+// withEmpty returns a response without body.
+func withEmpty(status int, description string) openapi3.NewResponsesOption {
+	return openapi3.WithStatus(status, &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription(description)})
+}
+
+// schemaRef returns a reference to the component schema named name.
+func schemaRef(name string) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+}
+
+// OpenAPIHandler serves the OpenAPI document and, optionally, an interactive documentation page
+// rendering it.
+type OpenAPIHandler struct {
+	document  []byte
+	assetsDir string // Directory holding the Swagger UI files of the documentation page.
+}
+
+// NewOpenAPIHandler creates a new OpenAPIHandler serving the document, and the Swagger UI files
+// of the documentation page from assetsDir.
+func NewOpenAPIHandler(doc *openapi3.T, assetsDir string) (*OpenAPIHandler, error) {
+	document, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode OpenAPI document: %w", err)
+	}
+	return &OpenAPIHandler{document: document, assetsDir: assetsDir}, nil
+}
+
+// DocsAssets lists the Swagger UI files, from the swagger-ui-dist package, served next to the
+// documentation page, with their content types.
+var DocsAssets = map[string]string{
+	"swagger-ui.css":       "text/css; charset=utf-8",
+	"swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+}
+
+// GetOpenAPI handles HTTP GET requests returning the OpenAPI document.
+func (h *OpenAPIHandler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(h.document)
+}
+
+// docsPage renders /openapi.json with Swagger UI, served by the service itself so that the page
+// runs no third-party code.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Delivery Order Price Calculator API</title>
+  <link rel="stylesheet" href="/docs/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/docs/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => { window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"}); };
+  </script>
+</body>
+</html>
+`
+
+// GetDocs handles HTTP GET requests returning the documentation page.
+func (h *OpenAPIHandler) GetDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(docsPage))
+}
+
+// GetDocsAsset handles HTTP GET requests returning a Swagger UI file of the documentation page.
+func (h *OpenAPIHandler) GetDocsAsset(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "file")
+	contentType, ok := DocsAssets[name]
+	if !ok {
+		http.Error(w, "Asset not found", http.StatusNotFound)
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(h.assetsDir, name))
+	if err != nil {
+		http.Error(w, "Asset not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI_Valid(t *testing.T) {
+	doc, err := OpenAPI()
+	if err != nil {
+		t.Fatalf("OpenAPI: %v", err)
+	}
+	assert.NoError(t, doc.Validate(context.Background()))
+
+	// Fields encoded without omitempty are required, and undocumented fields are rejected.
+	priceResponse := doc.Components.Schemas["PriceResponse"].Value
+	assert.ElementsMatch(t, []string{"total_price", "small_order_surcharge", "cart_value", "delivery"}, priceResponse.Required)
+	assert.False(t, *priceResponse.AdditionalProperties.Has)
+	delivery := priceResponse.Properties["delivery"].Value
+	assert.ElementsMatch(t, []string{"fee", "distance"}, delivery.Required)
+	assert.Contains(t, delivery.Properties, "mode")
+	assert.Equal(t, []string{"error"}, doc.Components.Schemas["ErrorResponse"].Value.Required)
+
+	operation := doc.Paths.Find("/api/v1/delivery-order-price").Get
+	if assert.NotNil(t, operation) {
+		assert.NotNil(t, operation.Parameters.GetByInAndName(openapi3.ParameterInQuery, "venue_slug"))
+		assert.NotNil(t, operation.Responses.Status(http.StatusUnprocessableEntity))
+		assert.Contains(t, operation.Responses.Status(http.StatusTooManyRequests).Value.Headers, "Retry-After")
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	doc, err := OpenAPI()
+	if err != nil {
+		t.Fatalf("OpenAPI: %v", err)
+	}
+	assetsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(assetsDir, "swagger-ui.css"), []byte("body {}"), 0o644); err != nil {
+		t.Fatalf("failed to write asset: %v", err)
+	}
+	handler, err := NewOpenAPIHandler(doc, assetsDir)
+	if err != nil {
+		t.Fatalf("NewOpenAPIHandler: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.GetOpenAPI(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	// The served document loads back and references the component schemas.
+	loaded, err := openapi3.NewLoader().LoadFromData(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("failed to load served document: %v", err)
+	}
+	assert.NoError(t, loaded.Validate(context.Background()))
+	assert.Equal(t, "#/components/schemas/PriceResponse",
+		loaded.Paths.Find("/api/v1/delivery-order-price").Get.Responses.Status(http.StatusOK).Value.Content.Get("application/json").Schema.Ref)
+
+	rec = httptest.NewRecorder()
+	handler.GetDocs(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+	assert.NotContains(t, rec.Body.String(), "https://")
+
+	// The Swagger UI files are served from the assets directory, and nothing else is.
+	r := chi.NewRouter()
+	r.Get("/docs/{file}", handler.GetDocsAsset)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/swagger-ui.css", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/css; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "body {}", rec.Body.String())
+	for _, target := range []string{"/docs/swagger-ui-bundle.js", "/docs/config.yaml"} {
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, target)
+	}
+}
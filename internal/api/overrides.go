@@ -0,0 +1,127 @@
+package api
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/overrides"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// OverrideStore defines the interface for listing and updating the venue pricing overrides.
+type OverrideStore interface {
+	List() []overrides.Override
+	Get(venueSlug string) (overrides.Override, bool)
+	Put(override overrides.Override, actor string) error
+	Delete(venueSlug, actor string) bool
+	Audit() []overrides.AuditEntry
+}
+
+// OverrideHandler is the HTTP handler of the administrative pricing override endpoints.
+type OverrideHandler struct {
+	store OverrideStore
+}
+
+// NewOverrideHandler creates a new OverrideHandler instance with the provided OverrideStore.
+func NewOverrideHandler(store OverrideStore) *OverrideHandler {
+	return &OverrideHandler{store: store}
+}
+
+// ListOverrides handles HTTP GET requests listing the pricing overrides of the venues the caller
+// may access.
+func (h *OverrideHandler) ListOverrides(w http.ResponseWriter, r *http.Request) {
+	list := []overrides.Override{}
+	for _, override := range h.store.List() {
+		if venueAllowed(r.Context(), override.VenueSlug) {
+			list = append(list, override)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// GetOverride handles HTTP GET requests returning the pricing override of the venue of the path.
+func (h *OverrideHandler) GetOverride(w http.ResponseWriter, r *http.Request) {
+	venueSlug := chi.URLParam(r, "venue_slug")
+	if !checkVenueAllowed(w, r, venueSlug) {
+		return
+	}
+	override, ok := h.store.Get(venueSlug)
+	if !ok {
+		http.Error(w, "Override not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, override)
+}
+
+// PutOverride handles HTTP PUT requests creating or replacing the pricing override of the venue
+// of the path. The body is an override; its venue_slug, if set, must match the path.
+func (h *OverrideHandler) PutOverride(w http.ResponseWriter, r *http.Request) {
+	venueSlug := chi.URLParam(r, "venue_slug")
+	if !checkVenueAllowed(w, r, venueSlug) {
+		return
+	}
+
+	var override overrides.Override
+	if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+		http.Error(w, "Invalid override body", http.StatusBadRequest)
+		return
+	}
+	if override.VenueSlug != "" && override.VenueSlug != venueSlug {
+		http.Error(w, "Override venue_slug does not match the path", http.StatusBadRequest)
+		return
+	}
+	override.VenueSlug = venueSlug
+
+	actor := actorFromContext(r.Context())
+	if err := h.store.Put(override, actor); err != nil {
+		if errors.Is(err, overrides.ErrInvalidOverride) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteOverride handles HTTP DELETE requests removing the pricing override of the venue of the path.
+func (h *OverrideHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	venueSlug := chi.URLParam(r, "venue_slug")
+	if !checkVenueAllowed(w, r, venueSlug) {
+		return
+	}
+	actor := actorFromContext(r.Context())
+	if !h.store.Delete(venueSlug, actor) {
+		http.Error(w, "Override not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListOverrideAudit handles HTTP GET requests listing the recorded changes of the overrides of
+// the venues the caller may access, oldest first.
+func (h *OverrideHandler) ListOverrideAudit(w http.ResponseWriter, r *http.Request) {
+	entries := []overrides.AuditEntry{}
+	for _, entry := range h.store.Audit() {
+		if venueAllowed(r.Context(), entry.VenueSlug) {
+			entries = append(entries, entry)
+		}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// actorFromContext returns the name of the authenticated caller recorded in the audit log: the
+// subject of its bearer token, or else the ID of its API key. It is empty without authentication.
+func actorFromContext(ctx context.Context) string {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if identity.Subject != "" {
+		return identity.Subject
+	}
+	return identity.KeyID
+}
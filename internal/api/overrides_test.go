@@ -0,0 +1,130 @@
+package api
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/overrides"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// newOverrideRouter routes the override endpoints to a handler backed by store.
+func newOverrideRouter(store OverrideStore) http.Handler {
+	handler := NewOverrideHandler(store)
+	r := chi.NewRouter()
+	r.Get("/admin/v1/overrides", handler.ListOverrides)
+	r.Get("/admin/v1/overrides/{venue_slug}", handler.GetOverride)
+	r.Put("/admin/v1/overrides/{venue_slug}", handler.PutOverride)
+	r.Delete("/admin/v1/overrides/{venue_slug}", handler.DeleteOverride)
+	r.Get("/admin/v1/audit/overrides", handler.ListOverrideAudit)
+	return r
+}
+
+func TestOverrideHandler(t *testing.T) {
+	store := overrides.NewStore()
+	router := newOverrideRouter(store)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "ops-key"}))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	const override = `{"venue_slug": "venue", "delivery_pricing": {"base_price": 0}, "order_minimum_no_surcharge": 500, "starts_at": "2030-01-07T10:00:00Z"}`
+	rec := serve(http.MethodPut, "/admin/v1/overrides/venue", `{"delivery_pricing": {"base_price": 0}, "order_minimum_no_surcharge": 500, "starts_at": "2030-01-07T10:00:00Z"}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(http.MethodGet, "/admin/v1/overrides/venue", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, override, rec.Body.String())
+
+	rec = serve(http.MethodGet, "/admin/v1/overrides", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[`+override+`]`, rec.Body.String())
+
+	rec = serve(http.MethodDelete, "/admin/v1/overrides/venue", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(http.MethodGet, "/admin/v1/overrides/venue", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = serve(http.MethodDelete, "/admin/v1/overrides/venue", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Both changes are audited with the API key making them.
+	rec = serve(http.MethodGet, "/admin/v1/audit/overrides", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var audit []overrides.AuditEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &audit); err != nil {
+		t.Fatalf("invalid audit log: %v", err)
+	}
+	if assert.Len(t, audit, 2) {
+		assert.Equal(t, overrides.ActionPut, audit[0].Action)
+		assert.Equal(t, overrides.ActionDelete, audit[1].Action)
+		assert.Equal(t, "ops-key", audit[1].Actor)
+		assert.Equal(t, "venue", audit[1].VenueSlug)
+	}
+}
+
+func TestOverrideHandler_PutInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"InvalidJSON", `{"order_minimum_no_surcharge":`},
+		{"MismatchedVenueSlug", `{"venue_slug": "other", "order_minimum_no_surcharge": 0}`},
+		{"NothingOverridden", `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := overrides.NewStore()
+			rec := httptest.NewRecorder()
+			newOverrideRouter(store).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/v1/overrides/venue", strings.NewReader(tt.body)))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Empty(t, store.List())
+		})
+	}
+}
+
+func TestOverrideHandler_VenueScope(t *testing.T) {
+	store := overrides.NewStore()
+	orderMinimum := 0
+	for _, venueSlug := range []string{"acme-helsinki", "other-venue"} {
+		if err := store.Put(overrides.Override{VenueSlug: venueSlug, OrderMinimumNoSurcharge: &orderMinimum}, "ops"); err != nil {
+			t.Fatalf("failed to store override: %v", err)
+		}
+	}
+	router := newOverrideRouter(store)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "acme-key", VenuePatterns: []string{"acme-*"}}))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Venues outside the scope of the key can be neither read nor changed.
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/admin/v1/overrides/other-venue", "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/admin/v1/overrides/other-venue", `{"order_minimum_no_surcharge": 0}`).Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, "/admin/v1/overrides/other-venue", "").Code)
+	_, ok := store.Get("other-venue")
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/admin/v1/overrides/acme-helsinki", "").Code)
+
+	// Lists only show the venues in scope.
+	var list []overrides.Override
+	assert.NoError(t, json.Unmarshal(serve(http.MethodGet, "/admin/v1/overrides", "").Body.Bytes(), &list))
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, "acme-helsinki", list[0].VenueSlug)
+	}
+	var audit []overrides.AuditEntry
+	assert.NoError(t, json.Unmarshal(serve(http.MethodGet, "/admin/v1/audit/overrides", "").Body.Bytes(), &audit))
+	if assert.Len(t, audit, 1) {
+		assert.Equal(t, "acme-helsinki", audit[0].VenueSlug)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/quotelog"
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// QuoteFinder defines the interface for looking up the recorded price quotes.
+type QuoteFinder interface {
+	Find(ctx context.Context, id string) ([]quotelog.Event, error)
+}
+
+// QuoteHandler is the HTTP handler of the administrative quote log endpoint.
+type QuoteHandler struct {
+	finder QuoteFinder
+}
+
+// NewQuoteHandler creates a new QuoteHandler instance with the provided QuoteFinder.
+func NewQuoteHandler(finder QuoteFinder) *QuoteHandler {
+	return &QuoteHandler{finder: finder}
+}
+
+// GetQuote handles HTTP GET requests returning the recorded calculations whose quote ID or
+// request ID is the id parameter, oldest first. Request IDs may contain slashes, so the ID is
+// a query parameter rather than part of the path. Callers restricted to some venues only get the
+// events of those venues.
+func (h *QuoteHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Missing required parameter: id", http.StatusBadRequest)
+		return
+	}
+	events, err := h.finder.Find(r.Context(), id)
+	if err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to look up quote",
+			slog.String("id", id),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "Failed to look up quote", http.StatusInternalServerError)
+		return
+	}
+	if len(events) == 0 {
+		http.Error(w, "Quote not found", http.StatusNotFound)
+		return
+	}
+	allowed := []quotelog.Event{}
+	for _, event := range events {
+		if venueAllowed(r.Context(), event.Order.Slug) {
+			allowed = append(allowed, event)
+		}
+	}
+	if len(allowed) == 0 {
+		http.Error(w, "Venue not allowed for this API key", http.StatusForbidden)
+		return
+	}
+	writeJSON(w, http.StatusOK, allowed)
+}
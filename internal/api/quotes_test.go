@@ -0,0 +1,88 @@
+package api
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/quotelog"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeQuoteFinder finds the events with the quote ID, or fails with err.
+type fakeQuoteFinder struct {
+	events []quotelog.Event
+	err    error
+}
+
+func (f fakeQuoteFinder) Find(_ context.Context, id string) ([]quotelog.Event, error) {
+	var found []quotelog.Event
+	for _, event := range f.events {
+		if event.QuoteID == id {
+			found = append(found, event)
+		}
+	}
+	return found, f.err
+}
+
+func TestQuoteHandler_GetQuote(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		finder     fakeQuoteFinder
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Found",
+			query:      "?id=quote-1",
+			finder:     fakeQuoteFinder{events: []quotelog.Event{{QuoteID: "quote-1", Error: "delivery is not possible, distance too long"}}},
+			wantStatus: http.StatusOK,
+		},
+		{name: "NotFound", query: "?id=quote-1", finder: fakeQuoteFinder{}, wantStatus: http.StatusNotFound, wantBody: "Quote not found\n"},
+		{name: "MissingID", finder: fakeQuoteFinder{}, wantStatus: http.StatusBadRequest, wantBody: "Missing required parameter: id\n"},
+		{name: "FinderError", query: "?id=quote-1", finder: fakeQuoteFinder{err: errors.New("disk error")}, wantStatus: http.StatusInternalServerError, wantBody: "Failed to look up quote\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewQuoteHandler(tt.finder).GetQuote(rec, httptest.NewRequest(http.MethodGet, "/admin/v1/quotes"+tt.query, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			} else {
+				assert.Contains(t, rec.Body.String(), `"quote_id":"quote-1"`)
+			}
+		})
+	}
+}
+
+func TestQuoteHandler_GetQuote_VenueScope(t *testing.T) {
+	finder := fakeQuoteFinder{events: []quotelog.Event{
+		{QuoteID: "quote-1", Order: models.OrderInfo{Slug: "acme-helsinki"}},
+		{QuoteID: "quote-1", Order: models.OrderInfo{Slug: "other-venue"}},
+		{QuoteID: "quote-2", Order: models.OrderInfo{Slug: "other-venue"}},
+	}}
+	serve := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/v1/quotes?id="+id, nil)
+		req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "acme-key", VenuePatterns: []string{"acme-*"}}))
+		rec := httptest.NewRecorder()
+		NewQuoteHandler(finder).GetQuote(rec, req)
+		return rec
+	}
+
+	// Only the events of the venues in scope are returned.
+	rec := serve("quote-1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"slug":"acme-helsinki"`)
+	assert.NotContains(t, rec.Body.String(), "other-venue")
+
+	rec = serve("quote-2")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// SlotService defines the interface for a service that prices orders at several delivery times.
+type SlotService interface {
+	// QuoteSlots prices the order at each delivery time, leaving out the times at which the
+	// venue does not deliver.
+	QuoteSlots(context.Context, *models.OrderInfo, []time.Time) ([]models.PriceResponse, error)
+}
+
+// slotsResponse is the body of the delivery slots endpoint.
+type slotsResponse struct {
+	VenueSlug string                 `json:"venue_slug"`
+	Slots     []models.PriceResponse `json:"slots"` // Price of each available slot, by start time.
+}
+
+// SlotHandler is the HTTP handler listing the delivery slots of a venue with their prices.
+type SlotHandler struct {
+	service  SlotService
+	interval time.Duration // Length of a slot.
+	horizon  time.Duration // How far ahead slots are listed.
+	now      func() time.Time
+}
+
+// NewSlotHandler creates a new SlotHandler listing slots of the interval up to horizon ahead.
+// They default to 30 minutes and 24 hours.
+func NewSlotHandler(service SlotService, interval, horizon time.Duration) *SlotHandler {
+	if interval <= 0 {
+		interval = 30 * time.Minute
+	}
+	if horizon <= 0 {
+		horizon = 24 * time.Hour
+	}
+	return &SlotHandler{service: service, interval: interval, horizon: horizon, now: time.Now}
+}
+
+// GetDeliverySlots handles HTTP GET requests listing the delivery slots of a venue for a location
+// and cart value. It takes the parameters of the price endpoint and an optional RFC 3339 from
+// parameter, and prices every slot starting from then, or now, within the horizon.
+func (h *SlotHandler) GetDeliverySlots(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "SlotHandler.GetDeliverySlots")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	orderInfo, ok := parseOrderInfo(w, r)
+	if !ok {
+		return
+	}
+
+	now := h.now()
+	from := now
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		var err error
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			http.Error(w, "Invalid from, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		if from.Before(now) {
+			from = now
+		}
+	}
+
+	span.SetAttributes(attribute.String("venue.slug", orderInfo.Slug), attribute.Int("order.cart_value", orderInfo.CartValue))
+
+	quotes, err := h.service.QuoteSlots(r.Context(), orderInfo, h.slotTimes(from, now))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, slotsResponse{VenueSlug: orderInfo.Slug, Slots: quotes})
+}
+
+// slotTimes returns the start times of the slots from the first one starting at or after from,
+// aligned on the interval, until the horizon after now. The times are in UTC.
+func (h *SlotHandler) slotTimes(from, now time.Time) []time.Time {
+	start := from.UTC().Truncate(h.interval)
+	if start.Before(from) {
+		start = start.Add(h.interval)
+	}
+	end := now.Add(h.horizon)
+
+	var times []time.Time
+	for t := start; t.Before(end); t = t.Add(h.interval) {
+		times = append(times, t)
+	}
+	return times
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSlotService prices every slot at the cart value and records the requested times.
+type fakeSlotService struct {
+	times []time.Time
+	err   error
+}
+
+func (f *fakeSlotService) QuoteSlots(_ context.Context, orderInfo *models.OrderInfo, times []time.Time) ([]models.PriceResponse, error) {
+	f.times = times
+	if f.err != nil {
+		return nil, f.err
+	}
+	quotes := make([]models.PriceResponse, len(times))
+	for i := range times {
+		quotes[i] = models.PriceResponse{TotalPrice: orderInfo.CartValue, CartValue: orderInfo.CartValue, ScheduledFor: &times[i]}
+	}
+	return quotes, nil
+}
+
+func TestSlotHandler_GetDeliverySlots(t *testing.T) {
+	service := &fakeSlotService{}
+	handler := NewSlotHandler(service, 30*time.Minute, 2*time.Hour)
+	now := time.Date(2030, time.January, 7, 10, 10, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	handler.GetDeliverySlots(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-slots?venue_slug=venue&user_lat=60.17&user_lon=24.93&cart_value=1000", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// Slots are aligned on the interval, from the next one until the horizon.
+	assert.Equal(t, []time.Time{
+		time.Date(2030, time.January, 7, 10, 30, 0, 0, time.UTC),
+		time.Date(2030, time.January, 7, 11, 0, 0, 0, time.UTC),
+		time.Date(2030, time.January, 7, 11, 30, 0, 0, time.UTC),
+		time.Date(2030, time.January, 7, 12, 0, 0, 0, time.UTC),
+	}, service.times)
+
+	var body slotsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "venue", body.VenueSlug)
+	if assert.Len(t, body.Slots, 4) {
+		assert.True(t, body.Slots[0].ScheduledFor.Equal(service.times[0]))
+	}
+}
+
+func TestSlotHandler_From(t *testing.T) {
+	service := &fakeSlotService{}
+	handler := NewSlotHandler(service, time.Hour, 3*time.Hour)
+	now := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	handler.GetDeliverySlots(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-slots?venue_slug=venue&user_lat=60.17&user_lon=24.93&cart_value=1000&from=2030-01-07T11:00:00Z", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []time.Time{
+		time.Date(2030, time.January, 7, 11, 0, 0, 0, time.UTC),
+		time.Date(2030, time.January, 7, 12, 0, 0, 0, time.UTC),
+	}, service.times)
+}
+
+func TestSlotHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+	}{
+		{name: "MissingCartValue", query: "venue_slug=venue&user_lat=60.17&user_lon=24.93", wantStatus: http.StatusBadRequest},
+		{name: "InvalidFrom", query: "venue_slug=venue&user_lat=60.17&user_lon=24.93&cart_value=1000&from=soon", wantStatus: http.StatusBadRequest},
+		{
+			name:       "NotDelivering",
+			query:      "venue_slug=venue&user_lat=60.17&user_lon=24.93&cart_value=1000",
+			err:        &availability.NotDeliveringError{Reason: availability.ReasonDeliveryDisabled},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewSlotHandler(&fakeSlotService{err: tt.err}, 0, 0)
+			rec := httptest.NewRecorder()
+			handler.GetDeliverySlots(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-slots?"+tt.query, nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package app
+
+import (
+	"backend-wolt-go/internal/api"
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/client"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/eta"
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/feerules"
+	"backend-wolt-go/internal/grpcapi"
+	"backend-wolt-go/internal/health"
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/metrics"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/overrides"
+	"backend-wolt-go/internal/quotelog"
+	"backend-wolt-go/internal/ratelimit"
+	"backend-wolt-go/internal/service"
+	"backend-wolt-go/internal/topk"
+	"backend-wolt-go/internal/zones"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+)
+
+// App is the delivery order price service: its HTTP routes and the background workers
+// keeping the venue data warm, built from the configuration.
+type App struct {
+	Handler    http.Handler // Serves every route of the service.
+	GRPCServer *grpc.Server // Serves the gRPC API, if enabled.
+
+	logger        *slog.Logger
+	checker       *health.Checker
+	venueProvider *service.VenueProvider
+	prefetcher    *service.Prefetcher // Refreshes the popular venues, if enabled.
+	quoteLog      *quotelog.FileSink  // Audit log of the price quotes, if enabled.
+	grpcHealth    *grpchealth.Server  // Health checking service of the gRPC server, if enabled.
+	routes        chi.Routes          // Routes served by Handler.
+
+	stopWorkers context.CancelFunc
+	workersDone chan struct{} // Closed once the background workers have stopped.
+}
+
+// New builds the service and its dependencies from the configuration. Tracing must be set up
+// beforehand, since the handlers use the global tracer provider.
+func New(config models.Config, logger *slog.Logger) (*App, error) {
+	a := &App{logger: logger}
+
+	// Create the Prometheus collectors shared by the router, the DOPC service and the venue provider.
+	m := metrics.New()
+
+	// Set up the client and upstream rate limiters, both backed by the in-memory store.
+	providerOpts := []service.Option{service.WithRecorder(m)}
+	if config.API.Timeout > 0 {
+		providerOpts = append(providerOpts, service.WithTimeout(config.API.Timeout))
+	}
+	var clientLimiter *ratelimit.Limiter
+	if config.RateLimit.Enabled {
+		// A zero rate or burst would reject every request, so it is a configuration mistake.
+		if err := validateRateLimit("client", config.RateLimit.Client); err != nil {
+			return nil, err
+		}
+		if err := validateRateLimit("upstream", config.RateLimit.Upstream); err != nil {
+			return nil, err
+		}
+		store := ratelimit.NewMemoryStore()
+		clientLimiter = ratelimit.NewLimiter(store, ratelimit.Limit(config.RateLimit.Client), "client:")
+		upstreamLimiter := ratelimit.NewLimiter(store, ratelimit.Limit(config.RateLimit.Upstream), "venue:")
+		providerOpts = append(providerOpts, service.WithLimiter(upstreamLimiter))
+	}
+
+	// Persist the last known good venue data so it can be served when the venue API is down.
+	if config.Snapshot.Enabled {
+		snapshotStore, err := service.NewFileSnapshotStore(config.Snapshot.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot store: %w", err)
+		}
+		providerOpts = append(providerOpts, service.WithSnapshots(snapshotStore, config.Snapshot.MaxAge))
+	}
+
+	// Cache venue API responses and refresh them in the background once they go stale.
+	if config.HTTPCache.Enabled {
+		providerOpts = append(providerOpts, service.WithHTTPCache(config.HTTPCache.DefaultMaxAge, config.HTTPCache.DefaultStaleWhileRevalidate))
+	}
+
+	// The venue API is the only venue source unless the configuration lists a fallback chain.
+	sourceChain := config.VenueSource.Chain
+	if len(sourceChain) == 0 {
+		sourceChain = []string{"http"}
+	}
+	usesVenueAPI := slices.Contains(sourceChain, "http")
+
+	// Track the most requested venues so that their cached data can be refreshed before it expires.
+	var popularVenues *topk.Sketch
+	if config.Prefetch.Enabled && config.HTTPCache.Enabled && usesVenueAPI {
+		trackedVenues := config.Prefetch.TrackedVenues
+		if trackedVenues <= 0 {
+			trackedVenues = 200
+		}
+		popularVenues = topk.New(trackedVenues)
+		providerOpts = append(providerOpts, service.WithPopularityTracker(popularVenues))
+	}
+
+	// Initialize the venue provider service with the base URL from the configuration.
+	a.venueProvider = service.NewVenueProvider(config.API.BaseURL, providerOpts...)
+	if popularVenues != nil {
+		a.prefetcher = service.NewPrefetcher(a.venueProvider, popularVenues, config.Prefetch)
+	}
+
+	// Combine the configured venue sources into a fallback chain.
+	venueSource, err := newVenueSource(sourceChain, config, a.venueProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up venue sources: %w", err)
+	}
+
+	// Load the polygon delivery zones of the venues, if any.
+	dopcOpts := []client.Option{client.WithRecorder(m)}
+	if config.DeliveryZones.File != "" || len(config.DeliveryZones.Venues) > 0 {
+		zoneStore, err := zones.Load(config.DeliveryZones)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load delivery zones: %w", err)
+		}
+		dopcOpts = append(dopcOpts, client.WithZones(zoneStore))
+	}
+
+	// Load the excluded areas, which can also be updated through the admin endpoints.
+	exclusionStore, err := exclusions.Load(config.Exclusions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exclusions: %w", err)
+	}
+	dopcOpts = append(dopcOpts, client.WithExclusions(exclusionStore))
+
+	// Apply the pricing overrides set through the admin endpoints on top of the venue data, and log
+	// every change as the durable audit trail.
+	overrideStore := overrides.NewStore(overrides.WithLogger(logger))
+	dopcOpts = append(dopcOpts, client.WithOverrides(overrideStore))
+
+	// Record every price calculation in the quote log, if enabled.
+	if config.QuoteLog.Enabled {
+		if config.QuoteLog.File == "" {
+			return nil, fmt.Errorf("quote_log.file is required when the quote log is enabled")
+		}
+		a.quoteLog, err = quotelog.NewFileSink(config.QuoteLog.File, int64(config.QuoteLog.MaxSizeMB)<<20, config.QuoteLog.MaxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open quote log: %w", err)
+		}
+		dopcOpts = append(dopcOpts, client.WithQuoteLog(a.quoteLog))
+	}
+
+	// Load the configured opening hours, which take precedence over those of the venue data.
+	if len(config.Availability.Venues) > 0 {
+		schedules, err := availability.LoadSchedules(config.Availability)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load opening hours: %w", err)
+		}
+		dopcOpts = append(dopcOpts, client.WithSchedules(schedules))
+	}
+
+	// Load the time-dependent fee rules, if any.
+	if len(config.FeeRules) > 0 {
+		rules, err := feerules.Load(config.FeeRules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fee rules: %w", err)
+		}
+		dopcOpts = append(dopcOpts, client.WithFeeRules(rules))
+	}
+
+	// Price orders with the fee tables of the courier modes, if any.
+	if len(config.CourierModes) > 0 {
+		modes, err := couriers.Load(config.CourierModes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load courier modes: %w", err)
+		}
+		dopcOpts = append(dopcOpts, client.WithCourierModes(modes))
+	}
+
+	// Estimate the delivery time of orders as soon as possible, if enabled.
+	if config.ETA.Enabled {
+		estimator, err := eta.NewEstimator(config.ETA, eta.WithQueueLoad(eta.StaticQueueLoad(config.ETA.QueueTimes)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up delivery time estimates: %w", err)
+		}
+		dopcOpts = append(dopcOpts, client.WithEstimator(estimator))
+	}
+
+	// Create a new DOPC (Delivery Order Price Calculator) service client using the venue sources.
+	dopcService := client.NewDOPC(venueSource, dopcOpts...)
+
+	// Create a new API handler and pass the DOPC service to it.
+	handler := api.NewHandler(dopcService)
+	slotHandler := api.NewSlotHandler(dopcService, config.Slots.Interval, config.Slots.Horizon)
+	exclusionHandler := api.NewExclusionHandler(exclusionStore)
+	overrideHandler := api.NewOverrideHandler(overrideStore)
+	openAPIDoc, err := api.OpenAPI()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+	openAPIHandler, err := api.NewOpenAPIHandler(openAPIDoc, config.OpenAPI.DocsAssetsDir)
+	if err != nil {
+		return nil, err
+	}
+	if config.OpenAPI.DocsUI {
+		for name := range api.DocsAssets {
+			if _, err := os.Stat(filepath.Join(config.OpenAPI.DocsAssetsDir, name)); err != nil {
+				return nil, fmt.Errorf("openapi.docs_ui needs the Swagger UI files in openapi.docs_assets_dir, see scripts/fetch-swagger-ui.sh: %w", err)
+			}
+		}
+	}
+
+	// Register the readiness checks for the configuration and the upstream venue API.
+	checkTimeout := config.Health.CheckTimeout
+	if checkTimeout <= 0 {
+		checkTimeout = 2 * time.Second
+	}
+	a.checker = health.NewChecker(checkTimeout)
+	a.checker.Register("config", func(ctx context.Context) error {
+		if usesVenueAPI && config.API.BaseURL == "" {
+			return errors.New("api.base_url is not configured")
+		}
+		return nil
+	})
+	if usesVenueAPI {
+		a.checker.Register("upstream", a.venueProvider.Ping)
+	}
+	a.checker.Register("venue_cache", a.venueProvider.SnapshotsWarmed)
+
+	// Build the authentication middleware: bearer tokens when enabled, falling back to API keys.
+	var (
+		authMiddleware func(http.Handler) http.Handler
+		keyStore       auth.KeyStore
+		validator      *auth.JWTValidator
+	)
+	if config.Auth.Enabled {
+		fileKeyStore, err := auth.LoadFileKeyStore(config.Auth.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load API keys: %w", err)
+		}
+		keyStore = fileKeyStore
+		authMiddleware = auth.APIKeyMiddleware(keyStore, config.Auth.Header, m)
+	}
+	if config.Auth.JWT.Enabled {
+		refreshInterval := config.Auth.JWT.RefreshInterval
+		if refreshInterval <= 0 {
+			refreshInterval = time.Hour
+		}
+		jwks := auth.NewJWKSCache(config.Auth.JWT.JWKSURL, refreshInterval)
+		validator = auth.NewJWTValidator(jwks, config.Auth.JWT.Issuer, config.Auth.JWT.Audience, config.Auth.JWT.Leeway)
+		authMiddleware = auth.BearerMiddleware(validator, authMiddleware)
+	}
+
+	// Serve the same DOPC service over gRPC, if enabled, authenticated with the same credentials.
+	if config.GRPC.Enabled {
+		var grpcOpts []grpcapi.Option
+		if keyStore != nil || validator != nil {
+			grpcOpts = append(grpcOpts, grpcapi.WithAuthenticator(grpcapi.NewAuthenticator(keyStore, config.Auth.Header, validator)))
+		}
+		if clientLimiter != nil {
+			grpcOpts = append(grpcOpts, grpcapi.WithLimiter(clientLimiter))
+		}
+		a.GRPCServer, a.grpcHealth = grpcapi.NewGRPCServer(dopcService, logger, grpcOpts...)
+	}
+
+	// Create a new router using the chi router package.
+	r := chi.NewRouter()
+
+	// Add middleware for request IDs, structured logging, request metrics and recovering from panics.
+	r.Use(middleware.RequestID)
+	r.Use(logging.Middleware(logger))
+	r.Use(m.Middleware)
+	r.Use(middleware.Recoverer)
+
+	// Define the liveness and readiness probes for the orchestrator.
+	r.Get("/healthz", a.checker.Liveness)
+	r.Get("/readyz", a.checker.Readiness)
+
+	// Expose the Prometheus metrics.
+	r.Method(http.MethodGet, "/metrics", m.Handler())
+
+	// Describe the routes with the OpenAPI document, and render it if the docs UI is enabled.
+	r.Get("/openapi.json", openAPIHandler.GetOpenAPI)
+	if config.OpenAPI.DocsUI {
+		r.Get("/docs", openAPIHandler.GetDocs)
+		r.Get("/docs/{file}", openAPIHandler.GetDocsAsset)
+	}
+
+	// Define the HTTP GET routes for fetching delivery order prices and slots, authenticated and rate limited per client.
+	r.Group(func(r chi.Router) {
+		if authMiddleware != nil {
+			r.Use(authMiddleware)
+		}
+		r.Use(auth.RequireScope(auth.ScopePricingRead))
+		if clientLimiter != nil {
+			r.Use(ratelimit.Middleware(clientLimiter, ratelimit.IdentityOrIP))
+		}
+		r.Get("/api/v1/delivery-order-price", handler.GetDeliveryOrderPrice)
+		r.Get("/api/v1/delivery-slots", slotHandler.GetDeliverySlots)
+	})
+
+	// Define the administrative routes for the excluded areas, the pricing overrides and the quote log.
+	// They always require an authenticated identity, so they are closed when authentication is disabled.
+	r.Group(func(r chi.Router) {
+		if authMiddleware != nil {
+			r.Use(authMiddleware)
+		}
+		r.Use(auth.RequireIdentity)
+		r.Use(auth.RequireScope(auth.ScopePricingAdmin))
+		r.Get("/admin/v1/exclusions", exclusionHandler.ListExclusions)
+		r.Put("/admin/v1/exclusions/{id}", exclusionHandler.PutExclusion)
+		r.Delete("/admin/v1/exclusions/{id}", exclusionHandler.DeleteExclusion)
+		r.Get("/admin/v1/overrides", overrideHandler.ListOverrides)
+		r.Get("/admin/v1/overrides/{venue_slug}", overrideHandler.GetOverride)
+		r.Put("/admin/v1/overrides/{venue_slug}", overrideHandler.PutOverride)
+		r.Delete("/admin/v1/overrides/{venue_slug}", overrideHandler.DeleteOverride)
+		r.Get("/admin/v1/audit/overrides", overrideHandler.ListOverrideAudit)
+		if a.quoteLog != nil {
+			r.Get("/admin/v1/quotes", api.NewQuoteHandler(a.quoteLog).GetQuote)
+		}
+	})
+
+	a.routes = r
+	a.Handler = otelhttp.NewHandler(r, "http.server")
+	return a, nil
+}
+
+// Start runs the background workers: warming the venue snapshots, after which readiness
+// reports the venue cache as up, and refreshing the popular venues if enabled.
+func (a *App) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopWorkers = cancel
+	a.workersDone = make(chan struct{})
+
+	go func() {
+		defer close(a.workersDone)
+
+		if err := a.venueProvider.WarmSnapshots(ctx); err != nil {
+			a.logger.Warn("failed to warm venue snapshots", slog.String("error", err.Error()))
+		}
+		if a.prefetcher != nil {
+			a.prefetcher.Run(ctx)
+		}
+	}()
+}
+
+// SetShuttingDown makes readiness fail, and the gRPC health checks report not serving, so that
+// the orchestrator stops routing traffic.
+func (a *App) SetShuttingDown() {
+	a.checker.SetShuttingDown()
+	if a.grpcHealth != nil {
+		a.grpcHealth.Shutdown()
+	}
+}
+
+// Stop stops the background workers and the revalidations of the HTTP cache, and waits for them
+// and the pending venue snapshot writes until ctx is done, then closes the quote log.
+func (a *App) Stop(ctx context.Context) error {
+	if a.quoteLog != nil {
+		defer a.quoteLog.Close()
+	}
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+		select {
+		case <-a.workersDone:
+		case <-ctx.Done():
+			return fmt.Errorf("background workers did not stop: %w", ctx.Err())
+		}
+	}
+	if err := a.venueProvider.StopRevalidations(ctx); err != nil {
+		return err
+	}
+	return a.venueProvider.FlushSnapshots(ctx)
+}
+
+// validateRateLimit checks that the limit of the rate_limit section named name allows requests.
+func validateRateLimit(name string, limit models.RateLimitConfig) error {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return fmt.Errorf("rate_limit.%s must have a positive rate and burst, got rate %v and burst %d", name, limit.Rate, limit.Burst)
+	}
+	return nil
+}
+
+// newVenueSource builds the chain of venue sources named in the configuration, in order.
+// The "http" source is the venue API behind venueProvider. A single source is used directly,
+// so that its errors are not wrapped by the chain.
+func newVenueSource(names []string, config models.Config, venueProvider *service.VenueProvider) (service.VenueSource, error) {
+	sources := make([]service.NamedSource, 0, len(names))
+	for _, name := range names {
+		var source service.VenueSource
+		switch name {
+		case "http":
+			source = venueProvider
+		case "file":
+			fileSource, err := service.NewFileVenueSource(config.VenueSource.FixtureDir)
+			if err != nil {
+				return nil, err
+			}
+			source = fileSource
+		case "config":
+			configSource, err := service.NewConfigVenueSource(config.VenueSource.Venues)
+			if err != nil {
+				return nil, err
+			}
+			source = configSource
+		default:
+			return nil, fmt.Errorf("unknown venue source %q", name)
+		}
+		sources = append(sources, service.NamedSource{Name: name, Source: source})
+	}
+	if len(sources) == 1 {
+		return sources[0].Source, nil
+	}
+	return service.NewVenueSourceChain(sources...), nil
+}
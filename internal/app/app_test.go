@@ -0,0 +1,642 @@
+package app
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/grpcapi/dopcv1"
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/mockvenue"
+	"backend-wolt-go/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// update rewrites the golden files with the current responses: go test ./internal/app -update
+var update = flag.Bool("update", false, "update the golden files")
+
+// upstreamPlaceholder replaces the address of the mock venue API in the golden files.
+const upstreamPlaceholder = "{{upstream}}"
+
+// adminKey is the API key granted the pricing:admin scope by withAdminKey.
+const adminKey = "admin-secret"
+
+// goldenResponse is the part of a response compared against a golden file.
+type goldenResponse struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type"`
+	Body        json.RawMessage `json:"body"`
+}
+
+// withAdminKey enables API key authentication with adminKey, which may price every venue and use
+// the administrative routes. Requests must then send it in the X-API-Key header.
+func withAdminKey(t *testing.T, config *models.Config) {
+	t.Helper()
+	keyFile := filepath.Join(t.TempDir(), "api_keys.yaml")
+	content := "keys:\n  - id: admin\n    tenant: ops\n    sha256: " + auth.HashKey(adminKey) + "\n    scopes: [pricing:read, pricing:admin]\n"
+	if err := os.WriteFile(keyFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	config.Auth.Enabled = true
+	config.Auth.Header = "X-API-Key"
+	config.Auth.KeyFile = keyFile
+}
+
+// newTestApp builds the service against a mock venue API serving the fixtures in testdata/venues.
+// The options adjust the configuration before the service is built.
+func newTestApp(t *testing.T, options ...func(*models.Config)) (*App, *mockvenue.API, string) {
+	t.Helper()
+	venues, err := mockvenue.LoadDir("testdata/venues")
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+	upstream := mockvenue.New(venues)
+	server := mockvenue.NewServer(t, upstream)
+
+	var config models.Config
+	config.API.BaseURL = server.URL
+	config.API.Timeout = 200 * time.Millisecond
+	config.Exclusions.Areas = []models.ExclusionConfig{{
+		ID:        "harbour",
+		VenueSlug: "test-venue",
+		Reason:    "restricted_area",
+		Geometry: map[string]any{
+			"type":        "Polygon",
+			"coordinates": []any{[]any{[]any{24.95, 60.16}, []any{24.96, 60.16}, []any{24.96, 60.17}, []any{24.95, 60.17}, []any{24.95, 60.16}}},
+		},
+	}}
+	for _, option := range options {
+		option(&config)
+	}
+
+	logger, err := logging.New("error", io.Discard)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	app, err := New(config, logger)
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	app.Start()
+	t.Cleanup(func() { app.Stop(context.Background()) })
+	return app, upstream, server.URL
+}
+
+// captureGolden records the response in the golden format, replacing the upstream address so
+// that the files do not depend on the port of the mock venue API.
+func captureGolden(t *testing.T, rec *httptest.ResponseRecorder, upstreamURL string) []byte {
+	t.Helper()
+	contentType := rec.Header().Get("Content-Type")
+	bodyText := strings.ReplaceAll(rec.Body.String(), upstreamURL, upstreamPlaceholder)
+
+	var body json.RawMessage
+	if strings.HasPrefix(contentType, "application/json") {
+		body = json.RawMessage(bodyText)
+	} else {
+		body, _ = json.Marshal(bodyText)
+	}
+	data, err := json.MarshalIndent(goldenResponse{Status: rec.Code, ContentType: contentType, Body: body}, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+	return append(data, '\n')
+}
+
+func TestApp_DeliveryOrderPrice(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		fault *mockvenue.Fault
+	}{
+		{name: "success", query: "venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087"},
+		{name: "small_order_surcharge", query: "venue_slug=test-venue&cart_value=800&user_lat=60.17094&user_lon=24.93087"},
+		{name: "out_of_range", query: "venue_slug=test-venue&cart_value=1000&user_lat=60.2&user_lon=24.9"},
+		{name: "excluded_area", query: "venue_slug=test-venue&cart_value=1000&user_lat=60.165&user_lon=24.955"},
+		{name: "unknown_venue", query: "venue_slug=unknown-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087"},
+		{name: "missing_parameter", query: "venue_slug=test-venue&user_lat=60.17094&user_lon=24.93087"},
+		{
+			name:  "upstream_server_error",
+			query: "venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087",
+			fault: &mockvenue.Fault{Status: http.StatusServiceUnavailable},
+		},
+		{
+			name:  "upstream_timeout",
+			query: "venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087",
+			fault: &mockvenue.Fault{Endpoint: mockvenue.EndpointDynamic, Latency: time.Second},
+		},
+		{
+			name:  "malformed_payload",
+			query: "venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087",
+			fault: &mockvenue.Fault{Endpoint: mockvenue.EndpointStatic, Malformed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, upstream, upstreamURL := newTestApp(t)
+			if tt.fault != nil {
+				upstream.SetFault("", *tt.fault)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			app.Handler.ServeHTTP(rec, req)
+
+			got := captureGolden(t, rec, upstreamURL)
+			path := filepath.Join("testdata", "golden", tt.name+".json")
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			assert.JSONEq(t, string(want), string(got))
+			assert.NotEmpty(t, rec.Header().Get("X-Request-Id"))
+		})
+	}
+}
+
+func TestApp_Probes(t *testing.T) {
+	app, _, _ := newTestApp(t)
+
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Readiness waits for the snapshots to be warmed in the background.
+	assert.Eventually(t, func() bool {
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	app.SetShuttingDown()
+	rec = httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestApp_Metrics(t *testing.T) {
+	app, _, _ := newTestApp(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087", nil)
+	app.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, bytes.Contains(rec.Body.Bytes(), []byte(`dopc_upstream_request_duration_seconds_count{endpoint="static"} 1`)))
+}
+
+func TestApp_EstimatedDeliveryTime(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.ETA.Enabled = true
+	config.ETA.QueueTimes = map[string]time.Duration{"test-venue": 5 * time.Minute}
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087", nil))
+
+	// 10 minutes of preparation, 5 minutes of queue and 177 m walking at 5 km/h take 17 minutes.
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"estimated_delivery_minutes":{"expected":17,"min":13,"max":21}`)
+}
+
+func TestApp_CourierModes(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.CourierModes = []models.CourierModeConfig{
+		{Name: "bike", DistanceRanges: []models.DistanceRange{{Min: 0, Max: 5000, A: 100, B: 0}}},
+		{Name: "walking", DistanceRanges: []models.DistanceRange{{Min: 0, Max: 1000, A: 0, B: 0}}},
+	}
+	withAdminKey(t, &config)
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	serve := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087"+query, nil)
+		req.Header.Set("X-API-Key", adminKey)
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"delivery":{"fee":190,"distance":177,"mode":"walking"}`)
+
+	rec = serve("&courier_mode=bike")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"delivery":{"fee":290,"distance":177,"mode":"bike"}`)
+
+	rec = serve("&courier_mode=car")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"courier_mode_unavailable"`)
+
+	// The fee of the mode is added to the fee of the venue distance ranges, overrides included.
+	req := httptest.NewRequest(http.MethodPut, "/admin/v1/overrides/test-venue", strings.NewReader(`{"delivery_pricing": {"distance_ranges": [{"min": 0, "max": 10000, "a": 50, "b": 0}, {"min": 10000, "max": 0, "a": 0, "b": 0}]}}`))
+	req.Header.Set("X-API-Key", adminKey)
+	rec = httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"delivery":{"fee":240,"distance":177,"mode":"walking"}`)
+}
+
+func TestNew_InvalidRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   models.RateLimitConfig
+		upstream models.RateLimitConfig
+		wantErr  string
+	}{
+		{"ZeroClientRate", models.RateLimitConfig{Rate: 0, Burst: 10}, models.RateLimitConfig{Rate: 5, Burst: 10}, "rate_limit.client"},
+		{"NegativeClientBurst", models.RateLimitConfig{Rate: 5, Burst: -1}, models.RateLimitConfig{Rate: 5, Burst: 10}, "rate_limit.client"},
+		{"ZeroUpstreamBurst", models.RateLimitConfig{Rate: 5, Burst: 10}, models.RateLimitConfig{Rate: 5, Burst: 0}, "rate_limit.upstream"},
+		{"MissingUpstream", models.RateLimitConfig{Rate: 5, Burst: 10}, models.RateLimitConfig{}, "rate_limit.upstream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config models.Config
+			config.VenueSource.Chain = []string{"file"}
+			config.VenueSource.FixtureDir = "testdata/venues"
+			config.RateLimit.Enabled = true
+			config.RateLimit.Client = tt.client
+			config.RateLimit.Upstream = tt.upstream
+			_, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNew_InvalidCourierModes(t *testing.T) {
+	var config models.Config
+	config.CourierModes = []models.CourierModeConfig{{Name: "bike"}}
+	_, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Error(t, err)
+}
+
+func TestApp_GRPC(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.GRPC.Enabled = true
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go app.GRPCServer.Serve(lis)
+	defer app.GRPCServer.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// The gRPC API returns the same price as the REST endpoint.
+	resp, err := dopcv1.NewDeliveryPricingClient(conn).GetDeliveryOrderPrice(context.Background(), &dopcv1.GetDeliveryOrderPriceRequest{
+		VenueSlug: "test-venue", CartValue: 1000, UserLat: 60.17094, UserLon: 24.93087,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1190), resp.GetTotalPrice())
+	assert.Equal(t, int64(177), resp.GetDelivery().GetDistance())
+
+	// Health checks report not serving once shutting down.
+	healthClient := healthpb.NewHealthClient(conn)
+	check, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check.GetStatus())
+	app.SetShuttingDown()
+	check, err = healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check.GetStatus())
+}
+
+func TestNew_GRPCDisabled(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.NoError(t, err)
+	assert.Nil(t, app.GRPCServer)
+	app.SetShuttingDown()
+}
+
+func TestApp_GRPCWithAuthentication(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.GRPC.Enabled = true
+	withAdminKey(t, &config)
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go app.GRPCServer.Serve(lis)
+	defer app.GRPCServer.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	client := dopcv1.NewDeliveryPricingClient(conn)
+	req := &dopcv1.GetDeliveryOrderPriceRequest{VenueSlug: "test-venue", CartValue: 1000, UserLat: 60.17094, UserLon: 24.93087}
+
+	// Calls are authenticated with the API keys of the REST endpoint, sent as metadata.
+	_, err = client.GetDeliveryOrderPrice(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	resp, err := client.GetDeliveryOrderPrice(metadata.AppendToOutgoingContext(context.Background(), "x-api-key", adminKey), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1190), resp.GetTotalPrice())
+}
+
+func TestApp_DeliverySlots(t *testing.T) {
+	app, upstream, _ := newTestApp(t)
+
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-slots?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Slots []models.PriceResponse `json:"slots"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	// The default configuration lists 30 minute slots over 24 hours, all priced from one fetch.
+	assert.Len(t, body.Slots, 48)
+	assert.Equal(t, 1, upstream.Requests("test-venue", mockvenue.EndpointDynamic))
+}
+
+func TestApp_UpdateExclusions(t *testing.T) {
+	app, _, _ := newTestApp(t, func(config *models.Config) { withAdminKey(t, config) })
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("X-API-Key", adminKey)
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+	const priceURL = "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087"
+
+	rec := serve(http.MethodPut, "/admin/v1/exclusions/stadium", `{"reason": "event", "geometry": {"type": "Polygon", "coordinates": [[[24.93, 60.17], [24.94, 60.17], [24.94, 60.18], [24.93, 60.18], [24.93, 60.17]]]}}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(http.MethodGet, priceURL, "")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"event"`)
+
+	rec = serve(http.MethodGet, "/admin/v1/exclusions", "")
+	assert.Contains(t, rec.Body.String(), `"id":"harbour"`)
+	assert.Contains(t, rec.Body.String(), `"id":"stadium"`)
+
+	rec = serve(http.MethodDelete, "/admin/v1/exclusions/stadium", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(http.MethodGet, priceURL, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestApp_PricingOverrides(t *testing.T) {
+	app, _, _ := newTestApp(t, func(config *models.Config) { withAdminKey(t, config) })
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("X-API-Key", adminKey)
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+	const priceURL = "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087"
+
+	rec := serve(http.MethodPut, "/admin/v1/overrides/test-venue", `{"delivery_pricing": {"base_price": 100}, "order_minimum_no_surcharge": 1500}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(http.MethodGet, priceURL, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"small_order_surcharge":500`)
+	assert.Contains(t, rec.Body.String(), `"fee":100`)
+
+	rec = serve(http.MethodDelete, "/admin/v1/overrides/test-venue", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(http.MethodGet, priceURL, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"fee":190`)
+
+	rec = serve(http.MethodGet, "/admin/v1/audit/overrides", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"action":"put"`)
+	assert.Contains(t, rec.Body.String(), `"action":"delete"`)
+}
+
+func TestApp_QuoteLog(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.QuoteLog.Enabled = true
+	config.QuoteLog.File = filepath.Join(t.TempDir(), "quotes.jsonl")
+	withAdminKey(t, &config)
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	defer app.Stop(context.Background())
+	serve := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-API-Key", adminKey)
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var price models.PriceResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &price); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.NotEmpty(t, price.QuoteID)
+
+	// Failed calculations are found by the ID of their request.
+	rec = serve("/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.2&user_lon=24.9")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	requestID := rec.Header().Get("X-Request-Id")
+
+	for _, id := range []string{price.QuoteID, requestID} {
+		rec = serve("/admin/v1/quotes?id=" + url.QueryEscape(id))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), id)
+	}
+}
+
+func TestApp_AdminRequiresAuthentication(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.QuoteLog.Enabled = true
+	config.QuoteLog.File = filepath.Join(t.TempDir(), "quotes.jsonl")
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	defer app.Stop(context.Background())
+
+	// Without authentication configured the price endpoint is open, but the admin routes are closed.
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	requests := []struct{ method, target, body string }{
+		{http.MethodPut, "/admin/v1/overrides/test-venue", `{"delivery_pricing": {"base_price": 0}}`},
+		{http.MethodDelete, "/admin/v1/overrides/test-venue", ""},
+		{http.MethodGet, "/admin/v1/audit/overrides", ""},
+		{http.MethodPut, "/admin/v1/exclusions/everywhere", `{"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`},
+		{http.MethodDelete, "/admin/v1/exclusions/harbour", ""},
+		{http.MethodGet, "/admin/v1/quotes?id=x", ""},
+	}
+	for _, r := range requests {
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, httptest.NewRequest(r.method, r.target, strings.NewReader(r.body)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "%s %s", r.method, r.target)
+	}
+}
+
+func TestApp_FallsBackToFixtures(t *testing.T) {
+	upstream := mockvenue.New(nil)
+	upstream.SetFault("", mockvenue.Fault{Status: http.StatusBadGateway})
+	server := mockvenue.NewServer(t, upstream)
+
+	var config models.Config
+	config.API.BaseURL = server.URL
+	config.VenueSource.Chain = []string{"http", "file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087", nil)
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, upstream.Requests("test-venue", mockvenue.EndpointStatic))
+}
+
+func TestApp_DeliveryZones(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.DeliveryZones.Venues = map[string][]models.ZoneConfig{
+		"test-venue": {{
+			Name: "north",
+			A:    500,
+			Geometry: map[string]any{
+				"type":        "Polygon",
+				"coordinates": []any{[]any{[]any{24.8, 60.19}, []any{25.0, 60.19}, []any{25.0, 60.21}, []any{24.8, 60.21}, []any{24.8, 60.19}}},
+			},
+		}},
+	}
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+
+	// The location is beyond the distance ranges of the venue but inside its zone.
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.2&user_lon=24.9", nil)
+	rec := httptest.NewRecorder()
+	app.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"fee":690`)
+	assert.Contains(t, rec.Body.String(), `"zone":"north"`)
+}
+
+func TestApp_OpeningHours(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.Availability.Venues = map[string]models.ScheduleConfig{
+		"test-venue": {Timezone: "Europe/Helsinki", OpeningHours: map[string][]string{"monday": {"10:00-22:00"}}},
+	}
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	price := func(deliveryTime string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087&delivery_time="+deliveryTime, nil))
+		return rec
+	}
+
+	// 06:00 UTC is 08:00 in Helsinki, before the venue opens.
+	rec := price("2030-01-07T06:00:00Z")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"error": "venue is not currently delivering: venue_closed", "reason": "venue_closed", "next_available_at": "2030-01-07T10:00:00+02:00"}`, rec.Body.String())
+
+	rec = price("2030-01-07T10:00:00Z")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNew_InvalidDeliveryZones(t *testing.T) {
+	var config models.Config
+	config.DeliveryZones.File = "testdata/missing.geojson"
+
+	_, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.ErrorContains(t, err, "failed to load delivery zones")
+}
+
+func TestNew_DocsUIWithoutAssets(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.OpenAPI.DocsUI = true
+	config.OpenAPI.DocsAssetsDir = t.TempDir()
+
+	_, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.ErrorContains(t, err, "openapi.docs_ui needs the Swagger UI files")
+}
+
+func TestNew_UnknownVenueSource(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"carrier-pigeon"}
+
+	_, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.ErrorContains(t, err, `unknown venue source "carrier-pigeon"`)
+}
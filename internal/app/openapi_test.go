@@ -0,0 +1,181 @@
+package app
+
+import (
+	"backend-wolt-go/internal/api"
+	"backend-wolt-go/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// anyStatus accepts any documented status code in contract checks.
+const anyStatus = 0
+
+// TestApp_OpenAPIContract serves requests of every documented operation through the router and
+// checks that the requests the handlers accept, and only those, conform to the OpenAPI document,
+// and that every response conforms to it. It fails when a handler drifts from the document.
+func TestApp_OpenAPIContract(t *testing.T) {
+	var config models.Config
+	config.VenueSource.Chain = []string{"file"}
+	config.VenueSource.FixtureDir = "testdata/venues"
+	config.Exclusions.Areas = []models.ExclusionConfig{{
+		ID:        "harbour",
+		VenueSlug: "test-venue",
+		Reason:    "restricted_area",
+		Geometry: map[string]any{
+			"type":        "Polygon",
+			"coordinates": []any{[]any{[]any{24.95, 60.16}, []any{24.96, 60.16}, []any{24.96, 60.17}, []any{24.95, 60.17}, []any{24.95, 60.16}}},
+		},
+	}}
+	config.QuoteLog.Enabled = true
+	config.QuoteLog.File = filepath.Join(t.TempDir(), "quotes.jsonl")
+	config.OpenAPI.DocsUI = true
+	config.OpenAPI.DocsAssetsDir = t.TempDir()
+	for _, name := range []string{"swagger-ui.css", "swagger-ui-bundle.js"} {
+		if err := os.WriteFile(filepath.Join(config.OpenAPI.DocsAssetsDir, name), []byte("/* "+name+" */"), 0o644); err != nil {
+			t.Fatalf("failed to write asset: %v", err)
+		}
+	}
+	withAdminKey(t, &config)
+	app, err := New(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to build app: %v", err)
+	}
+	defer app.Stop(context.Background())
+
+	doc, err := api.OpenAPI()
+	if err != nil {
+		t.Fatalf("OpenAPI: %v", err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("failed to build OpenAPI router: %v", err)
+	}
+	for _, contentType := range []string{"text/html", "text/css", "text/javascript"} {
+		openapi3filter.RegisterBodyDecoder(contentType, openapi3filter.FileBodyDecoder)
+		defer openapi3filter.UnregisterBodyDecoder(contentType)
+	}
+	options := &openapi3filter.Options{
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+	}
+
+	covered := map[string]bool{}
+	check := func(method, target, body string, status int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("X-API-Key", adminKey)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		route, pathParams, err := router.FindRoute(req)
+		if err != nil {
+			t.Errorf("%s %s: route not documented: %v", method, target, err)
+			return nil
+		}
+		covered[route.Operation.OperationID] = true
+
+		// The handlers reject with 400 exactly the requests the document does not allow.
+		input := &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route, Options: options}
+		requestErr := openapi3filter.ValidateRequest(context.Background(), input)
+		if status == http.StatusBadRequest {
+			assert.Error(t, requestErr, "%s %s: request conforms to the document but is rejected", method, target)
+		} else {
+			assert.NoError(t, requestErr, "%s %s", method, target)
+		}
+		req.Body = io.NopCloser(strings.NewReader(body))
+
+		rec := httptest.NewRecorder()
+		app.Handler.ServeHTTP(rec, req)
+		if status != anyStatus {
+			assert.Equal(t, status, rec.Code, "%s %s: %s", method, target, rec.Body.String())
+		}
+		responseErr := openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.Code,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+			Options:                options,
+		})
+		assert.NoError(t, responseErr, "%s %s", method, target)
+		return rec
+	}
+
+	const order = "venue_slug=test-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087"
+	rec := check(http.MethodGet, "/api/v1/delivery-order-price?"+order, "", http.StatusOK)
+	check(http.MethodGet, "/api/v1/delivery-order-price?"+order+"&scheduled_for=2100-01-01T12:00:00Z", "", anyStatus)
+	check(http.MethodGet, "/api/v1/delivery-order-price?cart_value=1000&user_lat=60.17094&user_lon=24.93087", "", http.StatusBadRequest)
+	check(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=0&user_lat=60.17094&user_lon=24.93087", "", http.StatusBadRequest)
+	check(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=91&user_lon=24.93087", "", http.StatusBadRequest)
+	check(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=60.165&user_lon=24.955", "", http.StatusUnprocessableEntity)
+	check(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=test-venue&cart_value=1000&user_lat=61&user_lon=24.93087", "", http.StatusUnprocessableEntity)
+	check(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=unknown-venue&cart_value=1000&user_lat=60.17094&user_lon=24.93087", "", http.StatusNotFound)
+	check(http.MethodGet, "/api/v1/delivery-slots?"+order, "", http.StatusOK)
+	check(http.MethodGet, "/api/v1/delivery-slots?"+order+"&from=yesterday", "", http.StatusBadRequest)
+
+	check(http.MethodGet, "/healthz", "", http.StatusOK)
+	check(http.MethodGet, "/readyz", "", anyStatus)
+	check(http.MethodGet, "/metrics", "", http.StatusOK)
+	check(http.MethodGet, "/openapi.json", "", http.StatusOK)
+	check(http.MethodGet, "/docs", "", http.StatusOK)
+	check(http.MethodGet, "/docs/swagger-ui.css", "", http.StatusOK)
+	check(http.MethodGet, "/docs/swagger-ui-bundle.js", "", http.StatusOK)
+	check(http.MethodGet, "/docs/openapi.yaml", "", http.StatusNotFound)
+
+	const geometry = `{"type": "Polygon", "coordinates": [[[24.93, 60.17], [24.94, 60.17], [24.94, 60.18], [24.93, 60.18], [24.93, 60.17]]]}`
+	check(http.MethodPut, "/admin/v1/exclusions/stadium", `{"reason": "event", "geometry": `+geometry+`}`, http.StatusNoContent)
+	check(http.MethodPut, "/admin/v1/exclusions/stadium", `{"reason": "event"}`, http.StatusBadRequest)
+	check(http.MethodGet, "/admin/v1/exclusions", "", http.StatusOK)
+	check(http.MethodDelete, "/admin/v1/exclusions/stadium", "", http.StatusNoContent)
+	check(http.MethodDelete, "/admin/v1/exclusions/stadium", "", http.StatusNotFound)
+
+	check(http.MethodGet, "/admin/v1/audit/overrides", "", http.StatusOK)
+	check(http.MethodPut, "/admin/v1/overrides/test-venue", `{"delivery_pricing": {"base_price": 100}, "ends_at": "2100-01-01T00:00:00Z"}`, http.StatusNoContent)
+	check(http.MethodPut, "/admin/v1/overrides/test-venue", `{"order_minimum_no_surcharge": "many"}`, http.StatusBadRequest)
+	check(http.MethodGet, "/admin/v1/overrides", "", http.StatusOK)
+	check(http.MethodGet, "/admin/v1/overrides/test-venue", "", http.StatusOK)
+	check(http.MethodGet, "/admin/v1/audit/overrides", "", http.StatusOK)
+	check(http.MethodDelete, "/admin/v1/overrides/test-venue", "", http.StatusNoContent)
+	check(http.MethodGet, "/admin/v1/overrides/test-venue", "", http.StatusNotFound)
+	check(http.MethodDelete, "/admin/v1/overrides/test-venue", "", http.StatusNotFound)
+
+	var price models.PriceResponse
+	if rec != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), &price); err != nil {
+			t.Fatalf("invalid price response: %v", err)
+		}
+	}
+	check(http.MethodGet, "/admin/v1/quotes?id="+price.QuoteID, "", http.StatusOK)
+	check(http.MethodGet, "/admin/v1/quotes?id=unknown", "", http.StatusNotFound)
+	check(http.MethodGet, "/admin/v1/quotes", "", http.StatusBadRequest)
+
+	// Every documented operation is exercised above.
+	for _, path := range doc.Paths.InMatchingOrder() {
+		for method, operation := range doc.Paths.Value(path).Operations() {
+			assert.True(t, covered[operation.OperationID], "%s %s is not exercised", method, path)
+		}
+	}
+
+	// Every route of the router is documented.
+	err = chi.Walk(app.routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		req := httptest.NewRequest(method, strings.NewReplacer("{id}", "x", "{venue_slug}", "x", "{file}", "x").Replace(route), nil)
+		if _, _, err := router.FindRoute(req); err != nil {
+			t.Errorf("%s %s is not documented: %v", method, route, err)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownKey is returned by a KeyStore when the API key is not registered.
+var ErrUnknownKey = errors.New("unknown API key")
+
+// Identity is the authenticated caller attached to the request context.
+type Identity struct {
+	KeyID         string   // Identifier of the API key, safe to log. Empty for bearer tokens.
+	Subject       string   // Subject of the bearer token. Empty for API keys.
+	Tenant        string   // Tenant owning the key.
+	VenuePatterns []string // Glob patterns of the venue slugs the tenant may price.
+	Scopes        []string // Permissions granted to the caller.
+}
+
+// AllowsVenue reports whether the identity may request prices for the venue slug.
+// An identity without venue patterns is not restricted.
+func (i *Identity) AllowsVenue(venueSlug string) bool {
+	if len(i.VenuePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range i.VenuePatterns {
+		if ok, err := path.Match(pattern, venueSlug); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the identity was granted the scope.
+func (i *Identity) HasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyStore looks up the identity owning an API key.
+type KeyStore interface {
+	// Lookup returns the identity for the key, or ErrUnknownKey.
+	Lookup(ctx context.Context, apiKey string) (*Identity, error)
+}
+
+// keyFile is the YAML layout of an API key file.
+type keyFile struct {
+	Keys []struct {
+		ID            string   `yaml:"id"`             // Identifier of the key, safe to log.
+		Tenant        string   `yaml:"tenant"`         // Tenant owning the key.
+		SHA256        string   `yaml:"sha256"`         // Hex-encoded SHA-256 hash of the key.
+		VenuePatterns []string `yaml:"venue_patterns"` // Glob patterns of the allowed venue slugs.
+		Scopes        []string `yaml:"scopes"`         // Permissions granted to the key; defaults to pricing:read.
+	} `yaml:"keys"`
+}
+
+// FileKeyStore is a KeyStore loaded from a YAML file. Only hashes of the keys are stored.
+type FileKeyStore struct {
+	identities map[string]*Identity // Identities by hex-encoded SHA-256 hash of the key.
+}
+
+// LoadFileKeyStore reads the API keys from the YAML file at keyPath.
+func LoadFileKeyStore(keyPath string) (*FileKeyStore, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read key file: %w", err)
+	}
+
+	var file keyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("could not decode key file: %w", err)
+	}
+
+	store := &FileKeyStore{identities: make(map[string]*Identity, len(file.Keys))}
+	for _, key := range file.Keys {
+		hash := strings.ToLower(key.SHA256)
+		if key.ID == "" || len(hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid key entry %q: id and a hex SHA-256 hash are required", key.ID)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("invalid key entry %q: %w", key.ID, err)
+		}
+		for _, pattern := range key.VenuePatterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid venue pattern %q for key %q: %w", pattern, key.ID, err)
+			}
+		}
+		scopes := key.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{ScopePricingRead}
+		}
+		store.identities[hash] = &Identity{
+			KeyID:         key.ID,
+			Tenant:        key.Tenant,
+			VenuePatterns: key.VenuePatterns,
+			Scopes:        scopes,
+		}
+	}
+	return store, nil
+}
+
+// Lookup returns the identity owning the API key.
+func (s *FileKeyStore) Lookup(_ context.Context, apiKey string) (*Identity, error) {
+	identity, ok := s.identities[HashKey(apiKey)]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return identity, nil
+}
+
+// HashKey returns the hex-encoded SHA-256 hash of an API key, as stored in key files.
+func HashKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeKeyFile writes the content to a temporary key file and returns its path.
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	keyPath := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(keyPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	return keyPath
+}
+
+func TestLoadFileKeyStore(t *testing.T) {
+	keyPath := writeKeyFile(t, `
+keys:
+  - id: key-1
+    tenant: acme
+    sha256: `+HashKey("secret")+`
+    venue_patterns: ["acme-*"]
+`)
+
+	store, err := LoadFileKeyStore(keyPath)
+	assert.NoError(t, err)
+
+	identity, err := store.Lookup(context.Background(), "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", identity.KeyID)
+	assert.Equal(t, "acme", identity.Tenant)
+	assert.True(t, identity.HasScope(ScopePricingRead), "keys without scopes default to pricing:read")
+
+	_, err = store.Lookup(context.Background(), "other")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestLoadFileKeyStore_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Invalid YAML", content: "keys: [::"},
+		{name: "Missing hash", content: "keys:\n  - id: key-1\n"},
+		{name: "Invalid hash", content: "keys:\n  - id: key-1\n    sha256: " + "zz" + HashKey("x")[2:] + "\n"},
+		{name: "Invalid pattern", content: "keys:\n  - id: key-1\n    sha256: " + HashKey("x") + "\n    venue_patterns: [\"[\"]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFileKeyStore(writeKeyFile(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadFileKeyStore("non-existent.yaml")
+	assert.Error(t, err)
+}
+
+func TestIdentity_AllowsVenue(t *testing.T) {
+	restricted := &Identity{VenuePatterns: []string{"acme-*", "shared-venue"}}
+	assert.True(t, restricted.AllowsVenue("acme-helsinki"))
+	assert.True(t, restricted.AllowsVenue("shared-venue"))
+	assert.False(t, restricted.AllowsVenue("other-helsinki"))
+
+	unrestricted := &Identity{}
+	assert.True(t, unrestricted.AllowsVenue("anything"))
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUnknownSigningKey is returned when the JWKS does not contain the requested key ID.
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// jwk is a single JSON Web Key. Only RSA and EC public keys are supported.
+type jwk struct {
+	Kty string `json:"kty"` // Key type: "RSA" or "EC".
+	Kid string `json:"kid"` // Key ID referenced by the token header.
+	Use string `json:"use"` // Intended use; only "sig" or empty keys are loaded.
+	N   string `json:"n"`   // RSA modulus.
+	E   string `json:"e"`   // RSA public exponent.
+	Crv string `json:"crv"` // EC curve name.
+	X   string `json:"x"`   // EC x coordinate.
+	Y   string `json:"y"`   // EC y coordinate.
+}
+
+// JWKSCache fetches the signing keys of the identity provider and caches them. The keys are
+// refreshed periodically and whenever a token references an unknown key ID, so that key
+// rotation is picked up without a restart.
+type JWKSCache struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration // Maximum age of the cached keys.
+	minRefreshInterval time.Duration // Minimum time between two fetches, to bound refetches on unknown key IDs.
+	now                func() time.Time
+
+	mu         sync.Mutex
+	keys       map[string]crypto.PublicKey
+	fetchedAt  time.Time
+	refreshing chan struct{} // Closed once the fetch in progress completes; nil when none is.
+	refreshErr error         // Error of the last fetch, if it failed.
+}
+
+// NewJWKSCache creates a JWKSCache for the JWKS document at url.
+func NewJWKSCache(url string, refreshInterval time.Duration) *JWKSCache {
+	return &JWKSCache{
+		url:                url,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: 10 * time.Second,
+		now:                time.Now,
+	}
+}
+
+// Key returns the public key with the given key ID. The JWKS is fetched without holding the
+// lock, so that a slow identity provider does not block the lookups of cached keys, and
+// concurrent lookups needing a refresh wait for the same fetch.
+func (c *JWKSCache) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	now := c.now()
+	expired := c.keys == nil || now.Sub(c.fetchedAt) >= c.refreshInterval
+	_, known := c.keys[kid]
+	if expired || (!known && (c.refreshing != nil || now.Sub(c.fetchedAt) >= c.minRefreshInterval)) {
+		done := c.refreshing
+		if done == nil {
+			done = make(chan struct{})
+			c.refreshing, c.fetchedAt = done, now
+			c.mu.Unlock()
+			// The fetch is shared, so it is not canceled with the request starting it; the
+			// client timeout bounds it.
+			keys, err := c.fetch(context.WithoutCancel(ctx))
+			c.mu.Lock()
+			if err == nil {
+				c.keys = keys
+			}
+			c.refreshing, c.refreshErr = nil, err
+			close(done)
+		} else {
+			c.mu.Unlock()
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			c.mu.Lock()
+		}
+		// Keep using the previous keys if the identity provider is temporarily unavailable.
+		if c.keys == nil && c.refreshErr != nil {
+			err := c.refreshErr
+			c.mu.Unlock()
+			return nil, err
+		}
+	}
+	key, ok := c.keys[kid]
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSigningKey, kid)
+	}
+	return key, nil
+}
+
+// fetch downloads the JWKS and returns its signing keys by key ID.
+func (c *JWKSCache) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected JWKS status code: " + resp.Status)
+	}
+
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(document.Keys))
+	for _, k := range document.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q in JWKS: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+// publicKey converts the JWK into an RSA or ECDSA public key.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt decodes a base64url-encoded big-endian integer.
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jwksStub is a local identity provider serving a JWKS document with rotatable keys.
+type jwksStub struct {
+	mu      sync.Mutex
+	keys    []jwk
+	fetches int
+	hold    func() // Called before serving each fetch, if set.
+	server  *httptest.Server
+}
+
+func newJWKSStub(t *testing.T) *jwksStub {
+	t.Helper()
+	stub := &jwksStub{}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		hold := stub.hold
+		stub.mu.Unlock()
+		if hold != nil {
+			hold()
+		}
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		stub.fetches++
+		_ = json.NewEncoder(w).Encode(map[string][]jwk{"keys": stub.keys})
+	}))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+// setRSAKeys replaces the served keys.
+func (s *jwksStub) setRSAKeys(keys map[string]*rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = nil
+	for kid, key := range keys {
+		s.keys = append(s.keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+}
+
+func (s *jwksStub) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestJWKSCache_Key(t *testing.T) {
+	stub := newJWKSStub(t)
+	key := newRSAKey(t)
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k1": key})
+
+	cache := NewJWKSCache(stub.server.URL, time.Hour)
+
+	got, err := cache.Key(context.Background(), "k1")
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(got))
+
+	// Cached keys are served without refetching.
+	_, _ = cache.Key(context.Background(), "k1")
+	assert.Equal(t, 1, stub.fetchCount())
+}
+
+func TestJWKSCache_Rotation(t *testing.T) {
+	stub := newJWKSStub(t)
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k1": newRSAKey(t)})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewJWKSCache(stub.server.URL, time.Hour)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Key(context.Background(), "k1")
+	assert.NoError(t, err)
+
+	// A new key ID is not refetched before the minimum refresh interval.
+	rotated := newRSAKey(t)
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k2": rotated})
+	_, err = cache.Key(context.Background(), "k2")
+	assert.ErrorIs(t, err, ErrUnknownSigningKey)
+
+	now = now.Add(cache.minRefreshInterval)
+	got, err := cache.Key(context.Background(), "k2")
+	assert.NoError(t, err)
+	assert.True(t, rotated.PublicKey.Equal(got))
+	assert.Equal(t, 2, stub.fetchCount())
+}
+
+func TestJWKSCache_SlowRefresh(t *testing.T) {
+	k1, k2 := newRSAKey(t), newRSAKey(t)
+	stub := newJWKSStub(t)
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k1": k1})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewJWKSCache(stub.server.URL, time.Hour)
+	cache.now = func() time.Time { return now }
+	_, err := cache.Key(context.Background(), "k1")
+	assert.NoError(t, err)
+
+	// An unknown key ID triggers a refresh, which the provider holds.
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	stub.mu.Lock()
+	stub.hold = func() {
+		once.Do(func() { close(started) })
+		<-release
+	}
+	stub.mu.Unlock()
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k1": k1, "k2": k2})
+	now = now.Add(cache.minRefreshInterval)
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := cache.Key(context.Background(), "k2")
+			assert.NoError(t, err)
+			assert.True(t, k2.PublicKey.Equal(got))
+		}()
+	}
+	<-started
+
+	// Cached keys are served while the refresh is in progress.
+	got, err := cache.Key(context.Background(), "k1")
+	assert.NoError(t, err)
+	assert.True(t, k1.PublicKey.Equal(got))
+
+	// Lookups waiting for the refresh give up with their context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cache.Key(ctx, "k2")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Concurrent lookups share the fetch.
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 2, stub.fetchCount())
+}
+
+func TestJWKSCache_KeepsKeysWhenProviderFails(t *testing.T) {
+	stub := newJWKSStub(t)
+	key := newRSAKey(t)
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k1": key})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewJWKSCache(stub.server.URL, time.Minute)
+	cache.now = func() time.Time { return now }
+	_, err := cache.Key(context.Background(), "k1")
+	assert.NoError(t, err)
+
+	stub.server.Close()
+	now = now.Add(time.Hour)
+	got, err := cache.Key(context.Background(), "k1")
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(got))
+}
+
+func TestJWK_PublicKey_EC(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	got, err := jwk{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}.publicKey()
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(got))
+
+	_, err = jwk{Kty: "oct"}.publicKey()
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Scopes enforced on the routes of the service.
+const (
+	ScopePricingRead  = "pricing:read"
+	ScopePricingAdmin = "pricing:admin"
+)
+
+// KeySource returns the public key used to verify tokens signed with the given key ID.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// claims are the JWT claims read by the validator.
+type claims struct {
+	jwt.RegisteredClaims
+	Scope  string   `json:"scope"`  // Space-separated scopes (RFC 8693).
+	Scp    []string `json:"scp"`    // Scopes as a list, used by some identity providers.
+	Tenant string   `json:"tenant"` // Optional tenant of the caller.
+}
+
+// JWTValidator validates bearer tokens issued by the identity provider.
+type JWTValidator struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+// NewJWTValidator creates a validator checking the signature against keys, the issuer, the audience,
+// and the expiry of every token with the given clock skew leeway.
+func NewJWTValidator(keys KeySource, issuer, audience string, leeway time.Duration) *JWTValidator {
+	return &JWTValidator{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(leeway),
+		),
+	}
+}
+
+// Validate verifies the token and returns the identity of its subject.
+func (v *JWTValidator) Validate(ctx context.Context, token string) (*Identity, error) {
+	var c claims
+	_, err := v.parser.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no key ID")
+		}
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	scopes := append(strings.Fields(c.Scope), c.Scp...)
+	return &Identity{
+		Subject: c.Subject,
+		Tenant:  c.Tenant,
+		Scopes:  scopes,
+	}, nil
+}
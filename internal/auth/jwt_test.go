@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer   = "https://idp.test/"
+	testAudience = "dopc"
+)
+
+// signToken signs the claims with the key, using kid as the key ID.
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, c jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// validClaims returns claims accepted by a validator using testIssuer and testAudience.
+func validClaims(scope string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "service-a",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func newTestValidator(t *testing.T) (*JWTValidator, *rsa.PrivateKey) {
+	t.Helper()
+	stub := newJWKSStub(t)
+	key := newRSAKey(t)
+	stub.setRSAKeys(map[string]*rsa.PrivateKey{"k1": key})
+	return NewJWTValidator(NewJWKSCache(stub.server.URL, time.Hour), testIssuer, testAudience, 0), key
+}
+
+func TestJWTValidator_Validate(t *testing.T) {
+	validator, key := newTestValidator(t)
+	otherKey := newRSAKey(t)
+
+	expired := validClaims(ScopePricingRead)
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongIssuer := validClaims(ScopePricingRead)
+	wrongIssuer["iss"] = "https://evil.test/"
+	wrongAudience := validClaims(ScopePricingRead)
+	wrongAudience["aud"] = "other"
+	noExpiry := validClaims(ScopePricingRead)
+	delete(noExpiry, "exp")
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "Valid", token: signToken(t, key, "k1", validClaims(ScopePricingRead+" "+ScopePricingAdmin))},
+		{name: "Expired", token: signToken(t, key, "k1", expired), wantErr: true},
+		{name: "Wrong issuer", token: signToken(t, key, "k1", wrongIssuer), wantErr: true},
+		{name: "Wrong audience", token: signToken(t, key, "k1", wrongAudience), wantErr: true},
+		{name: "Missing expiry", token: signToken(t, key, "k1", noExpiry), wantErr: true},
+		{name: "Unknown key ID", token: signToken(t, key, "k2", validClaims(ScopePricingRead)), wantErr: true},
+		{name: "Wrong signature", token: signToken(t, otherKey, "k1", validClaims(ScopePricingRead)), wantErr: true},
+		{name: "Malformed", token: "not-a-token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := validator.Validate(context.Background(), tt.token)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "service-a", identity.Subject)
+			assert.True(t, identity.HasScope(ScopePricingRead))
+			assert.True(t, identity.HasScope(ScopePricingAdmin))
+		})
+	}
+}
+
+func TestBearerMiddleware(t *testing.T) {
+	validator, key := newTestValidator(t)
+	fallbackCalled := false
+	fallback := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fallbackCalled = true
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler := BearerMiddleware(validator, fallback)(RequireScope(ScopePricingAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name         string
+		header       string
+		wantStatus   int
+		wantFallback bool
+	}{
+		{name: "Admin scope", header: "Bearer " + signToken(t, key, "k1", validClaims(ScopePricingAdmin)), wantStatus: http.StatusOK},
+		{name: "Read scope only", header: "Bearer " + signToken(t, key, "k1", validClaims(ScopePricingRead)), wantStatus: http.StatusForbidden},
+		{name: "Invalid token", header: "Bearer invalid", wantStatus: http.StatusUnauthorized},
+		{name: "No token uses fallback", wantStatus: http.StatusOK, wantFallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallbackCalled = false
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantFallback, fallbackCalled)
+		})
+	}
+}
+
+func TestBearerMiddleware_NoFallback(t *testing.T) {
+	validator, _ := newTestValidator(t)
+	handler := BearerMiddleware(validator, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"backend-wolt-go/internal/logging"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// contextKey is the type of the context key holding the authenticated identity.
+type contextKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity.
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// IdentityFromContext returns the authenticated identity stored in ctx, if any.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(*Identity)
+	return identity, ok
+}
+
+// KeyRecorder receives the outcome of every request authenticated with an API key.
+type KeyRecorder interface {
+	ObserveKeyRequest(keyID string, status int)
+}
+
+// APIKeyMiddleware authenticates requests with the API key found in the given header. Requests
+// without a key or with an unknown key are rejected with 401 Unauthorized. The identity is attached
+// to the request context and its key ID and tenant are added to the request logs.
+func APIKeyMiddleware(store KeyStore, header string, recorder KeyRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get(header)
+			if apiKey == "" {
+				http.Error(w, "Missing API key", http.StatusUnauthorized)
+				return
+			}
+
+			identity, err := store.Lookup(r.Context(), apiKey)
+			if err != nil {
+				if !errors.Is(err, ErrUnknownKey) {
+					logging.FromContext(r.Context()).ErrorContext(r.Context(), "API key lookup failed", slog.String("error", err.Error()))
+					http.Error(w, "Authentication unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := logging.WithAttrs(r.Context(),
+				slog.String("key_id", identity.KeyID),
+				slog.String("tenant", identity.Tenant),
+			)
+			ctx = WithIdentity(ctx, identity)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			recorder.ObserveKeyRequest(identity.KeyID, status)
+		})
+	}
+}
+
+// BearerMiddleware authenticates requests carrying an "Authorization: Bearer" token. Requests
+// without a bearer token are passed to fallback when it is set (for example the API key
+// middleware) and rejected with 401 Unauthorized otherwise.
+func BearerMiddleware(validator *JWTValidator, fallback func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		var fallbackHandler http.Handler
+		if fallback != nil {
+			fallbackHandler = fallback(next)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				if fallbackHandler != nil {
+					fallbackHandler.ServeHTTP(w, r)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			identity, err := validator.Validate(r.Context(), token)
+			if err != nil {
+				logging.FromContext(r.Context()).InfoContext(r.Context(), "bearer token rejected", slog.String("error", err.Error()))
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, "Invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := logging.WithAttrs(r.Context(),
+				slog.String("subject", identity.Subject),
+				slog.String("tenant", identity.Tenant),
+			)
+			next.ServeHTTP(w, r.WithContext(WithIdentity(ctx, identity)))
+		})
+	}
+}
+
+// RequireIdentity rejects requests without an authenticated identity with 401 Unauthorized, so
+// that the routes it guards are closed when authentication is disabled.
+func RequireIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := IdentityFromContext(r.Context()); !ok {
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScope rejects authenticated requests whose identity lacks the scope with 403 Forbidden.
+// Requests without an identity are let through, so the middleware is a no-op when authentication
+// is disabled.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if identity, ok := IdentityFromContext(r.Context()); ok && !identity.HasScope(scope) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				http.Error(w, "Insufficient scope", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapKeyStore is a KeyStore backed by a map of plain keys.
+type mapKeyStore map[string]*Identity
+
+func (s mapKeyStore) Lookup(_ context.Context, apiKey string) (*Identity, error) {
+	if apiKey == "broken" {
+		return nil, errors.New("store unavailable")
+	}
+	identity, ok := s[apiKey]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return identity, nil
+}
+
+// fakeKeyRecorder records the observed key requests.
+type fakeKeyRecorder struct {
+	observed map[string]int
+}
+
+func (f *fakeKeyRecorder) ObserveKeyRequest(keyID string, status int) {
+	f.observed[keyID] = status
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	store := mapKeyStore{"secret": {KeyID: "key-1", Tenant: "acme"}}
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		wantStatus int
+	}{
+		{name: "Missing key", wantStatus: http.StatusUnauthorized},
+		{name: "Unknown key", apiKey: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "Store failure", apiKey: "broken", wantStatus: http.StatusServiceUnavailable},
+		{name: "Valid key", apiKey: "secret", wantStatus: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakeKeyRecorder{observed: map[string]int{}}
+			var gotIdentity *Identity
+			handler := APIKeyMiddleware(store, "X-API-Key", recorder)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIdentity, _ = IdentityFromContext(r.Context())
+				w.WriteHeader(http.StatusAccepted)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusAccepted {
+				assert.Equal(t, "key-1", gotIdentity.KeyID)
+				assert.Equal(t, map[string]int{"key-1": http.StatusAccepted}, recorder.observed)
+			} else {
+				assert.Nil(t, gotIdentity)
+				assert.Empty(t, recorder.observed)
+			}
+		})
+	}
+}
+
+func TestRequireIdentity(t *testing.T) {
+	handler := RequireIdentity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req.WithContext(WithIdentity(req.Context(), &Identity{KeyID: "key-1"})))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,179 @@
+package availability
+
+import (
+	"backend-wolt-go/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	// Embed the time zone database, so that venue time zones resolve on hosts without one.
+	_ "time/tzdata"
+)
+
+// Reason codes of a NotDeliveringError.
+const (
+	ReasonClosed           = "venue_closed"      // The delivery time is outside the opening hours.
+	ReasonDeliveryDisabled = "delivery_disabled" // The venue has turned delivery off.
+)
+
+// NotDeliveringError is returned when the venue does not deliver at the requested time.
+type NotDeliveringError struct {
+	Reason          string    // Reason code, ReasonClosed or ReasonDeliveryDisabled.
+	NextAvailableAt time.Time // Next time the venue delivers, zero if unknown.
+}
+
+func (e *NotDeliveringError) Error() string {
+	return "venue is not currently delivering: " + e.Reason
+}
+
+// weekdays maps the lower case weekday names used in opening hours to their time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// interval is an opening interval in minutes since midnight. close is after open, and above
+// 24 hours when the interval runs past midnight.
+type interval struct {
+	open, close int
+}
+
+// Schedule holds the weekly opening hours of a venue in its time zone.
+type Schedule struct {
+	location *time.Location
+	days     [7][]interval // Intervals by weekday, ordered by opening time.
+}
+
+// ParseSchedule parses opening hours given by lower case weekday as "HH:MM-HH:MM" intervals in
+// the IANA time zone, or in UTC when it is empty. An interval closing at or before its opening
+// time runs past midnight, and "24:00" closes at the end of the day. Weekdays without intervals
+// are closed.
+func ParseSchedule(timezone string, hours map[string][]string) (*Schedule, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+	}
+
+	s := &Schedule{location: location}
+	for day, intervals := range hours {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", day)
+		}
+		for _, text := range intervals {
+			iv, err := parseInterval(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid opening hours %q on %s: %w", text, day, err)
+			}
+			s.days[weekday] = append(s.days[weekday], iv)
+		}
+		sort.Slice(s.days[weekday], func(i, j int) bool { return s.days[weekday][i].open < s.days[weekday][j].open })
+	}
+	return s, nil
+}
+
+// parseInterval parses an "HH:MM-HH:MM" interval.
+func parseInterval(text string) (interval, error) {
+	openText, closeText, ok := strings.Cut(text, "-")
+	if !ok {
+		return interval{}, fmt.Errorf("want HH:MM-HH:MM")
+	}
+	open, err := parseClock(strings.TrimSpace(openText))
+	if err != nil {
+		return interval{}, err
+	}
+	closing, err := parseClock(strings.TrimSpace(closeText))
+	if err != nil {
+		return interval{}, err
+	}
+	if open == 24*60 {
+		return interval{}, fmt.Errorf("opening time must be before 24:00")
+	}
+	if closing <= open {
+		closing += 24 * 60
+	}
+	return interval{open: open, close: closing}, nil
+}
+
+// parseClock parses an "HH:MM" time of day, up to "24:00", into minutes since midnight.
+func parseClock(text string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(text, "%2d:%2d", &hour, &minute); err != nil || len(text) != 5 {
+		return 0, fmt.Errorf("invalid time of day %q", text)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time of day %q", text)
+	}
+	return hour*60 + minute, nil
+}
+
+// Location returns the time zone of the schedule.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// IsOpen reports whether the venue is open at t.
+func (s *Schedule) IsOpen(t time.Time) bool {
+	local := t.In(s.location)
+	minute := local.Hour()*60 + local.Minute()
+	for _, iv := range s.days[local.Weekday()] {
+		if minute >= iv.open && minute < iv.close {
+			return true
+		}
+	}
+	// Intervals of the previous day running past midnight.
+	for _, iv := range s.days[(local.Weekday()+6)%7] {
+		if minute+24*60 >= iv.open && minute+24*60 < iv.close {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns t if the venue is open at t, and otherwise the next time it opens within a
+// week. It reports false when the venue never opens.
+func (s *Schedule) NextOpen(t time.Time) (time.Time, bool) {
+	if s.IsOpen(t) {
+		return t, true
+	}
+	local := t.In(s.location)
+	year, month, day := local.Date()
+	for offset := 0; offset <= 7; offset++ {
+		date := time.Date(year, month, day+offset, 0, 0, 0, 0, s.location)
+		for _, iv := range s.days[date.Weekday()] {
+			opening := time.Date(year, month, day+offset, iv.open/60, iv.open%60, 0, 0, s.location)
+			if opening.After(t) {
+				return opening, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Schedules holds the configured opening hours by venue slug.
+type Schedules map[string]*Schedule
+
+// LoadSchedules parses the opening hours of the configuration.
+func LoadSchedules(config models.AvailabilityConfig) (Schedules, error) {
+	schedules := make(Schedules, len(config.Venues))
+	for venueSlug, scheduleConfig := range config.Venues {
+		schedule, err := ParseSchedule(scheduleConfig.Timezone, scheduleConfig.OpeningHours)
+		if err != nil {
+			return nil, fmt.Errorf("opening hours of venue %s: %w", venueSlug, err)
+		}
+		schedules[venueSlug] = schedule
+	}
+	return schedules, nil
+}
+
+// Schedule returns the configured opening hours of the venue, if any.
+func (s Schedules) Schedule(venueSlug string) (*Schedule, bool) {
+	schedule, ok := s[venueSlug]
+	return schedule, ok
+}
@@ -0,0 +1,125 @@
+package availability
+
+import (
+	"backend-wolt-go/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// helsinki returns the time in Helsinki on the day of January 2030, which starts on a Tuesday.
+func helsinki(t *testing.T, day, hour, minute int) time.Time {
+	t.Helper()
+	location, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	return time.Date(2030, time.January, day, hour, minute, 0, 0, location)
+}
+
+func TestSchedule_IsOpen(t *testing.T) {
+	schedule, err := ParseSchedule("Europe/Helsinki", map[string][]string{
+		"monday":   {"10:00-14:00", "17:00-22:00"},
+		"friday":   {"18:00-02:00"},
+		"saturday": {"00:00-24:00"},
+	})
+	if err != nil {
+		t.Fatalf("ParseSchedule: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"MondayMorning", helsinki(t, 7, 9, 59), false},
+		{"MondayOpening", helsinki(t, 7, 10, 0), true},
+		{"MondayBreak", helsinki(t, 7, 15, 0), false},
+		{"MondayEvening", helsinki(t, 7, 21, 59), true},
+		{"MondayClosing", helsinki(t, 7, 22, 0), false},
+		{"Tuesday", helsinki(t, 8, 12, 0), false},
+		{"FridayNight", helsinki(t, 4, 23, 0), true},
+		{"SaturdayAfterMidnight", helsinki(t, 5, 1, 30), true},
+		{"SaturdayEvening", helsinki(t, 5, 23, 59), true},
+		{"SundayNight", helsinki(t, 6, 1, 0), false},
+		{"OtherTimeZone", helsinki(t, 7, 10, 30).UTC(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, schedule.IsOpen(tt.at))
+		})
+	}
+}
+
+func TestSchedule_NextOpen(t *testing.T) {
+	schedule, err := ParseSchedule("Europe/Helsinki", map[string][]string{
+		"monday": {"17:00-22:00", "10:00-14:00"},
+	})
+	if err != nil {
+		t.Fatalf("ParseSchedule: %v", err)
+	}
+
+	next, ok := schedule.NextOpen(helsinki(t, 7, 11, 0))
+	assert.True(t, ok)
+	assert.Equal(t, helsinki(t, 7, 11, 0), next, "open now")
+
+	next, _ = schedule.NextOpen(helsinki(t, 7, 15, 0))
+	assert.Equal(t, helsinki(t, 7, 17, 0), next, "after the break")
+
+	next, _ = schedule.NextOpen(helsinki(t, 7, 23, 0))
+	assert.Equal(t, helsinki(t, 14, 10, 0), next, "next week")
+	assert.Equal(t, "Europe/Helsinki", next.Location().String())
+
+	closed, err := ParseSchedule("", nil)
+	if err != nil {
+		t.Fatalf("ParseSchedule: %v", err)
+	}
+	_, ok = closed.NextOpen(helsinki(t, 7, 12, 0))
+	assert.False(t, ok)
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		hours    map[string][]string
+	}{
+		{"UnknownTimeZone", "Mars/Olympus_Mons", nil},
+		{"UnknownWeekday", "UTC", map[string][]string{"someday": {"10:00-12:00"}}},
+		{"MissingDash", "UTC", map[string][]string{"monday": {"10:00"}}},
+		{"InvalidHour", "UTC", map[string][]string{"monday": {"25:00-26:00"}}},
+		{"InvalidMinute", "UTC", map[string][]string{"monday": {"10:60-12:00"}}},
+		{"ShortForm", "UTC", map[string][]string{"monday": {"9:00-12:00"}}},
+		{"OpeningAtMidnight", "UTC", map[string][]string{"monday": {"24:00-02:00"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.timezone, tt.hours)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadSchedules(t *testing.T) {
+	schedules, err := LoadSchedules(models.AvailabilityConfig{Venues: map[string]models.ScheduleConfig{
+		"venue": {Timezone: "Europe/Helsinki", OpeningHours: map[string][]string{"monday": {"10:00-22:00"}}},
+	}})
+	if err != nil {
+		t.Fatalf("LoadSchedules: %v", err)
+	}
+
+	schedule, ok := schedules.Schedule("venue")
+	if assert.True(t, ok) {
+		assert.True(t, schedule.IsOpen(helsinki(t, 7, 12, 0)))
+	}
+	_, ok = schedules.Schedule("other-venue")
+	assert.False(t, ok)
+
+	_, err = LoadSchedules(models.AvailabilityConfig{Venues: map[string]models.ScheduleConfig{
+		"venue": {Timezone: "Nowhere/City"},
+	}})
+	assert.ErrorContains(t, err, "venue")
+}
@@ -1,43 +1,329 @@
 package client
 
 import (
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/logging"
 	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/overrides"
+	"backend-wolt-go/internal/quotelog"
 	"backend-wolt-go/internal/utils"
+	"backend-wolt-go/internal/zones"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the spans for the price calculation.
+var tracer = otel.Tracer("backend-wolt-go/internal/client")
+
+// ErrInvalidVenueLocation is returned when the venue data does not carry a longitude and latitude.
+var ErrInvalidVenueLocation = errors.New("venue location is invalid")
+
 // VenueProvider defines an interface for retrieving venue information.
 type VenueProvider interface {
 	// GetVenueInformation retrieves static and dynamic information for a given venue by its slug.
 	GetVenueInformation(ctx context.Context, venueSlug string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error)
 }
 
+// PricingRecorder receives the outcome of every price calculation.
+type PricingRecorder interface {
+	// ObserveQuote records the distance and delivery fee of a successfully priced order.
+	ObserveQuote(distance, fee int)
+	// ObserveOutOfRange records an order rejected because the user is outside the delivery range.
+	ObserveOutOfRange(venueSlug string)
+}
+
+// Option configures optional dependencies of a DOPC.
+type Option func(*DOPC)
+
+// WithRecorder sets the recorder notified about every price calculation.
+func WithRecorder(recorder PricingRecorder) Option {
+	return func(d *DOPC) {
+		d.recorder = recorder
+	}
+}
+
+// ZoneMatcher finds the polygon delivery zone of a venue containing a location.
+type ZoneMatcher interface {
+	Match(venueSlug string, lat, lon float64) (zones.Zone, bool)
+}
+
+// WithZones prices orders inside a delivery zone of their venue with the fee components of the
+// zone. Orders outside of every zone are priced with the distance ranges of the venue.
+func WithZones(matcher ZoneMatcher) Option {
+	return func(d *DOPC) {
+		d.zones = matcher
+	}
+}
+
+// ExclusionChecker rejects locations that are never delivered to.
+type ExclusionChecker interface {
+	// Check returns an error, such as an *exclusions.NotServiceableError, if the venue does not
+	// deliver to the location.
+	Check(venueSlug string, lat, lon float64) error
+}
+
+// WithExclusions rejects orders to excluded areas before they are priced.
+func WithExclusions(checker ExclusionChecker) Option {
+	return func(d *DOPC) {
+		d.exclusions = checker
+	}
+}
+
+// ScheduleSource provides configured opening hours that take precedence over those of the venue data.
+type ScheduleSource interface {
+	Schedule(venueSlug string) (*availability.Schedule, bool)
+}
+
+// WithSchedules uses the configured opening hours of the venues instead of those of the venue data.
+func WithSchedules(schedules ScheduleSource) Option {
+	return func(d *DOPC) {
+		d.schedules = schedules
+	}
+}
+
+// FeeRules modifies the delivery fee depending on the delivery time.
+type FeeRules interface {
+	// Apply returns the delivery fee of the venue at the time and the names of the applied rules.
+	Apply(venueSlug string, t time.Time, fee int) (int, []string)
+}
+
+// WithFeeRules applies time-dependent fee rules, such as surges, to the delivery fee.
+func WithFeeRules(rules FeeRules) Option {
+	return func(d *DOPC) {
+		d.feeRules = rules
+	}
+}
+
+// DeliveryEstimator estimates how long the delivery of an order takes.
+type DeliveryEstimator interface {
+	// Estimate returns the delivery time of an order of the venue over the distance in meters,
+	// by the courier mode if it is set.
+	Estimate(ctx context.Context, venueSlug string, distance int, mode string) models.DeliveryEstimate
+}
+
+// WithEstimator adds the estimated delivery time to the prices of orders as soon as possible.
+func WithEstimator(estimator DeliveryEstimator) Option {
+	return func(d *DOPC) {
+		d.estimator = estimator
+	}
+}
+
+// CourierModes picks the courier transport mode of an order and its delivery fee.
+type CourierModes interface {
+	// Choose returns the cheapest mode serving the distance, or the requested mode if set, with
+	// its fee added to the fee of the venue.
+	Choose(distance, venueFee int, requested string) (couriers.Choice, error)
+}
+
+// WithCourierModes adds the fee table of the cheapest or requested courier mode to the fee
+// computed from the distance ranges of the venue, which still limit the serviceable distance.
+func WithCourierModes(modes CourierModes) Option {
+	return func(d *DOPC) {
+		d.couriers = modes
+	}
+}
+
+// PricingOverrides provides the overrides of the venue pricing set by operators.
+type PricingOverrides interface {
+	// Active returns the override of the venue if it applies at t.
+	Active(venueSlug string, t time.Time) (overrides.Override, bool)
+}
+
+// WithOverrides applies the pricing overrides active at the delivery time on top of the venue data.
+func WithOverrides(source PricingOverrides) Option {
+	return func(d *DOPC) {
+		d.overrides = source
+	}
+}
+
+// QuoteSink receives the audit events of the price calculations.
+type QuoteSink interface {
+	Write(ctx context.Context, event quotelog.Event) error
+}
+
+// WithQuoteLog records every price calculation, successful or not, to the sink and reports the
+// quote ID in the prices.
+func WithQuoteLog(sink QuoteSink) Option {
+	return func(d *DOPC) {
+		d.quoteLog = sink
+	}
+}
+
+// nopRecorder is used when no PricingRecorder is configured.
+type nopRecorder struct{}
+
+func (nopRecorder) ObserveQuote(int, int)    {}
+func (nopRecorder) ObserveOutOfRange(string) {}
+
 // DOPC (Delivery Order Price Calculator) is responsible for calculating delivery fees.
 type DOPC struct {
 	venueProvider VenueProvider
+	recorder      PricingRecorder
+	zones         ZoneMatcher       // Polygon delivery zones of the venues, if set.
+	exclusions    ExclusionChecker  // Areas that are never delivered to, if set.
+	schedules     ScheduleSource    // Configured opening hours of the venues, if set.
+	feeRules      FeeRules          // Time-dependent fee rules, if set.
+	estimator     DeliveryEstimator // Delivery time estimates, if set.
+	couriers      CourierModes      // Fee tables of the courier modes, if set.
+	overrides     PricingOverrides  // Pricing overrides of the venues, if set.
+	quoteLog      QuoteSink         // Audit log of the price calculations, if set.
+	now           func() time.Time
 }
 
-// NewDOPC creates a new instance of the DOPC struct with the provided VenueProvider.
-func NewDOPC(venueProvider VenueProvider) *DOPC {
-	return &DOPC{venueProvider: venueProvider}
+// NewDOPC creates a new instance of the DOPC struct with the provided VenueProvider and options.
+func NewDOPC(venueProvider VenueProvider, opts ...Option) *DOPC {
+	d := &DOPC{venueProvider: venueProvider, recorder: nopRecorder{}, now: time.Now}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // CalculateDeliveryFee calculates the delivery fee based on order information.
 // It retrieves venue data, calculates the distance between the venue and the user,
 // determines the delivery fee, small order surcharge, and total price.
-func (d *DOPC) CalculateDeliveryFee(ctx context.Context, orderInfo *models.OrderInfo) (models.PriceResponse, error) {
+func (d *DOPC) CalculateDeliveryFee(ctx context.Context, orderInfo *models.OrderInfo) (response models.PriceResponse, err error) {
+	ctx, span := tracer.Start(ctx, "DOPC.CalculateDeliveryFee", trace.WithAttributes(
+		attribute.String("venue.slug", orderInfo.Slug),
+		attribute.Int("order.cart_value", orderInfo.CartValue),
+	))
+	defer func() { endSpan(span, err) }()
+
+	var event *quotelog.Event
+	if d.quoteLog != nil {
+		event = quotelog.NewEvent(ctx, orderInfo, d.now())
+		span.SetAttributes(attribute.String("quote.id", event.QuoteID))
+		defer func() { d.recordQuote(ctx, event, &response, err) }()
+	}
+
+	staticResponse, dynamicResponse, err := d.venueInformation(ctx, orderInfo)
+	if err != nil {
+		return models.PriceResponse{}, err
+	}
+	if event != nil {
+		event.VenueVersion = quotelog.VenueVersion(staticResponse, dynamicResponse)
+		if !dynamicResponse.FetchedAt.IsZero() {
+			fetchedAt := dynamicResponse.FetchedAt
+			event.VenueFetchedAt = &fetchedAt
+		}
+	}
+
+	return d.calculate(ctx, orderInfo, staticResponse, dynamicResponse, event)
+}
+
+// recordQuote completes the event with the outcome of the calculation and writes it to the quote
+// log, setting the quote ID of a successful price. A failing quote log is logged and does not
+// fail the calculation.
+func (d *DOPC) recordQuote(ctx context.Context, event *quotelog.Event, response *models.PriceResponse, err error) {
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		response.QuoteID = event.QuoteID
+		result := *response
+		event.Result = &result
+	}
+	if err := d.quoteLog.Write(ctx, *event); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "failed to record quote",
+			slog.String("quote_id", event.QuoteID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// QuoteSlots prices the order for delivery at each of the given times, fetching the venue data
+// once. Times at which the venue does not deliver are left out of the returned prices.
+func (d *DOPC) QuoteSlots(ctx context.Context, orderInfo *models.OrderInfo, deliveryTimes []time.Time) (_ []models.PriceResponse, err error) {
+	ctx, span := tracer.Start(ctx, "DOPC.QuoteSlots", trace.WithAttributes(
+		attribute.String("venue.slug", orderInfo.Slug),
+		attribute.Int("slots.count", len(deliveryTimes)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	staticResponse, dynamicResponse, err := d.venueInformation(ctx, orderInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]models.PriceResponse, 0, len(deliveryTimes))
+	for _, deliveryTime := range deliveryTimes {
+		slotOrder := *orderInfo
+		slotOrder.DeliveryTime = deliveryTime
+		quote, err := d.calculate(ctx, &slotOrder, staticResponse, dynamicResponse, nil)
+		var notDeliveringErr *availability.NotDeliveringError
+		if errors.As(err, &notDeliveringErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, nil
+}
+
+// venueInformation retrieves the venue data of the order, rejecting addresses in excluded areas
+// before fetching it.
+func (d *DOPC) venueInformation(ctx context.Context, orderInfo *models.OrderInfo) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	if d.exclusions != nil {
+		if err := d.exclusions.Check(orderInfo.Slug, orderInfo.Lat, orderInfo.Lon); err != nil {
+			logging.FromContext(ctx).InfoContext(ctx, "delivery address rejected",
+				slog.String("venue_slug", orderInfo.Slug),
+				slog.String("error", err.Error()),
+			)
+			return nil, nil, err
+		}
+	}
+
 	// Retrieve venue information (static and dynamic) for the given venue slug.
 	staticResponse, dynamicResponse, err := d.venueProvider.GetVenueInformation(ctx, orderInfo.Slug)
 	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "failed to get venue information",
+			slog.String("venue_slug", orderInfo.Slug),
+			slog.String("error", err.Error()),
+		)
+		return nil, nil, err
+	}
+	return staticResponse, dynamicResponse, nil
+}
+
+// calculate prices the order against the venue data. It runs in its own span so that the
+// calculation step can be told apart from the upstream fetches. The quote event, if not nil, is
+// annotated with the details of the calculation.
+func (d *DOPC) calculate(ctx context.Context, orderInfo *models.OrderInfo, staticResponse *models.VenueStaticResponse, dynamicResponse *models.VenueDynamicResponse, event *quotelog.Event) (_ models.PriceResponse, err error) {
+	ctx, span := tracer.Start(ctx, "DOPC.calculate")
+	defer func() { endSpan(span, err) }()
+
+	// Reject orders the venue cannot deliver at the requested time.
+	if err := d.checkAvailability(ctx, orderInfo, staticResponse, dynamicResponse); err != nil {
+		logging.FromContext(ctx).InfoContext(ctx, "venue not delivering",
+			slog.String("venue_slug", orderInfo.Slug),
+			slog.String("error", err.Error()),
+		)
 		return models.PriceResponse{}, err
 	}
 
 	// Extract venue coordinates from the static response.
+	if len(staticResponse.VenueRaw.Location.Coordinates) < 2 {
+		return models.PriceResponse{}, fmt.Errorf("%w: venue %s has %d coordinates", ErrInvalidVenueLocation, orderInfo.Slug, len(staticResponse.VenueRaw.Location.Coordinates))
+	}
 	venueLon := staticResponse.VenueRaw.Location.Coordinates[0]
 	venueLat := staticResponse.VenueRaw.Location.Coordinates[1]
 
 	// Calculate the distance between the venue and the user's location.
 	distance := utils.CalculateDistance(venueLat, venueLon, orderInfo.Lat, orderInfo.Lon)
+	span.SetAttributes(attribute.Int("delivery.distance", distance))
+	if event != nil {
+		event.Distance = distance
+	}
 
 	// Map the distance ranges from the dynamic response to a usable format.
 	distanceRanges := make([]models.DistanceRange, len(dynamicResponse.VenueRaw.DeliverySpecs.DeliveryPricing.DistanceRanges))
@@ -50,29 +336,204 @@ func (d *DOPC) CalculateDeliveryFee(ctx context.Context, orderInfo *models.Order
 		}
 	}
 
-	// Calculate the delivery fee based on the distance, base price, and distance ranges.
-	deliveryFee, err := utils.CalculateDeliveryFee(distance, dynamicResponse.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice, distanceRanges)
+	basePrice := dynamicResponse.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice
+	orderMinimumNoSurcharge := dynamicResponse.VenueRaw.DeliverySpecs.OrderMinimumNoSurcharge
+
+	// Replace the upstream values overridden by operators at the delivery time.
+	if d.overrides != nil {
+		if override, ok := d.overrides.Active(orderInfo.Slug, d.deliveryTime(orderInfo)); ok {
+			if pricing := override.DeliveryPricing; pricing != nil {
+				if pricing.BasePrice != nil {
+					basePrice = *pricing.BasePrice
+				}
+				if len(pricing.DistanceRanges) > 0 {
+					distanceRanges = pricing.DistanceRanges
+				}
+			}
+			if override.OrderMinimumNoSurcharge != nil {
+				orderMinimumNoSurcharge = *override.OrderMinimumNoSurcharge
+			}
+			span.SetAttributes(attribute.Bool("venue.overridden", true))
+			if event != nil {
+				event.Overridden = true
+			}
+		}
+	}
+
+	// Price the order with the fee components of its delivery zone if it is in one, unless a
+	// courier mode is requested. Otherwise, price it with the distance ranges of the venue, adding
+	// the fee of the cheapest or requested courier mode if modes are configured.
+	var (
+		deliveryFee int
+		zoneName    string
+		mode        string
+		matched     *quotelog.MatchedRange
+	)
+	zone, inZone := d.matchZone(orderInfo)
+	switch {
+	case inZone && orderInfo.CourierMode == "":
+		zoneName = zone.Name
+		deliveryFee = zone.Fee(basePrice, distance)
+		matched = &quotelog.MatchedRange{Zone: zone.Name, DistanceRange: models.DistanceRange{A: zone.A, B: zone.B}}
+		span.SetAttributes(attribute.String("delivery.zone", zone.Name))
+	case d.couriers != nil:
+		// The distance ranges of the venue still bound the distance it delivers to.
+		var (
+			venueFee int
+			choice   couriers.Choice
+		)
+		venueFee, err = utils.CalculateDeliveryFee(distance, basePrice, distanceRanges)
+		if err == nil {
+			choice, err = d.couriers.Choose(distance, venueFee, orderInfo.CourierMode)
+		}
+		if err == nil {
+			deliveryFee, mode = choice.Fee, choice.Mode
+			matched = &quotelog.MatchedRange{Index: choice.Index, Mode: choice.Mode, DistanceRange: choice.Range}
+			span.SetAttributes(attribute.String("delivery.mode", mode))
+		}
+	case orderInfo.CourierMode != "":
+		err = &couriers.ModeUnavailableError{Mode: orderInfo.CourierMode}
+	default:
+		if i, ok := utils.FindDistanceRange(distance, distanceRanges); ok {
+			matched = &quotelog.MatchedRange{Index: i, DistanceRange: distanceRanges[i]}
+		}
+
+		// Calculate the delivery fee based on the distance, base price, and distance ranges.
+		deliveryFee, err = utils.CalculateDeliveryFee(distance, basePrice, distanceRanges)
+	}
+	if matched != nil && matched.Zone == "" {
+		span.SetAttributes(
+			attribute.Int("delivery.range.index", matched.Index),
+			attribute.Int("delivery.range.min", matched.Min),
+			attribute.Int("delivery.range.max", matched.Max),
+		)
+	}
+	if event != nil {
+		event.Range = matched
+	}
 	if err != nil {
+		if errors.Is(err, utils.ErrDeliveryNotPossible) {
+			d.recorder.ObserveOutOfRange(orderInfo.Slug)
+		}
+		logging.FromContext(ctx).InfoContext(ctx, "delivery fee calculation rejected",
+			slog.String("venue_slug", orderInfo.Slug),
+			slog.Int("distance", distance),
+			slog.String("error", err.Error()),
+		)
 		return models.PriceResponse{}, err
 	}
 
+	// Apply the time-dependent fee rules, such as surges, active at the delivery time.
+	var appliedRules []string
+	if d.feeRules != nil {
+		deliveryFee, appliedRules = d.feeRules.Apply(orderInfo.Slug, d.deliveryTime(orderInfo), deliveryFee)
+		if len(appliedRules) > 0 {
+			span.SetAttributes(attribute.StringSlice("delivery.rules", appliedRules))
+		}
+	}
+
+	d.recorder.ObserveQuote(distance, deliveryFee)
+	span.SetAttributes(attribute.Int("delivery.fee", deliveryFee))
+	logging.FromContext(ctx).DebugContext(ctx, "delivery fee calculated",
+		slog.String("venue_slug", orderInfo.Slug),
+		slog.Float64("lat", orderInfo.Lat),
+		slog.Float64("lon", orderInfo.Lon),
+		slog.Int("distance", distance),
+		slog.Int("fee", deliveryFee),
+	)
+
 	// Calculate the small order surcharge if the cart value is below the minimum threshold.
-	smallOrderSurcharge := utils.CalculateSmallOrderSurcharge(orderInfo.CartValue, dynamicResponse.VenueRaw.DeliverySpecs.OrderMinimumNoSurcharge)
+	smallOrderSurcharge := utils.CalculateSmallOrderSurcharge(orderInfo.CartValue, orderMinimumNoSurcharge)
 
 	// Calculate the total price, including cart value, small order surcharge, and delivery fee.
 	totalPrice := utils.CalculateTotalPrice(orderInfo.CartValue, smallOrderSurcharge, deliveryFee)
 
 	// Return the calculated price response.
-	return models.PriceResponse{
+	response := models.PriceResponse{
 		TotalPrice:          totalPrice,
 		SmallOrderSurcharge: smallOrderSurcharge,
 		CartValue:           orderInfo.CartValue,
-		Delivery: struct {
-			Fee      int `json:"fee"`
-			Distance int `json:"distance"`
-		}{
-			Fee:      deliveryFee,
-			Distance: int(distance),
-		},
-	}, nil
+		Stale:               staticResponse.Stale || dynamicResponse.Stale,
+	}
+	response.Delivery.Fee = deliveryFee
+	response.Delivery.Distance = distance
+	response.Delivery.Zone = zoneName
+	response.Delivery.Rules = appliedRules
+	response.Delivery.Mode = mode
+	if !orderInfo.DeliveryTime.IsZero() {
+		scheduledFor := orderInfo.DeliveryTime
+		response.ScheduledFor = &scheduledFor
+	} else if d.estimator != nil {
+		estimate := d.estimator.Estimate(ctx, orderInfo.Slug, distance, mode)
+		response.EstimatedDeliveryMinutes = &estimate
+	}
+	return response, nil
+}
+
+// checkAvailability returns a *availability.NotDeliveringError if the venue does not deliver at the
+// delivery time of the order, or now for orders as soon as possible. The delivery_enabled flag of
+// the venue data only applies to orders as soon as possible, since it describes the present.
+func (d *DOPC) checkAvailability(ctx context.Context, orderInfo *models.OrderInfo, staticResponse *models.VenueStaticResponse, dynamicResponse *models.VenueDynamicResponse) error {
+	deliveryTime := d.deliveryTime(orderInfo)
+	if orderInfo.DeliveryTime.IsZero() {
+		if enabled := dynamicResponse.VenueRaw.DeliverySpecs.DeliveryEnabled; enabled != nil && !*enabled {
+			return &availability.NotDeliveringError{Reason: availability.ReasonDeliveryDisabled}
+		}
+	}
+
+	schedule, ok := d.venueSchedule(ctx, orderInfo.Slug, staticResponse)
+	if !ok || schedule.IsOpen(deliveryTime) {
+		return nil
+	}
+	nextAvailableAt, _ := schedule.NextOpen(deliveryTime)
+	return &availability.NotDeliveringError{Reason: availability.ReasonClosed, NextAvailableAt: nextAvailableAt}
+}
+
+// deliveryTime returns the requested delivery time of the order, or now for orders as soon as possible.
+func (d *DOPC) deliveryTime(orderInfo *models.OrderInfo) time.Time {
+	if orderInfo.DeliveryTime.IsZero() {
+		return d.now()
+	}
+	return orderInfo.DeliveryTime
+}
+
+// venueSchedule returns the configured opening hours of the venue, or else those of the venue
+// data. Venues without opening hours deliver at any time. Invalid opening hours in the venue data
+// are logged and ignored, so that a bad entry upstream does not close the venue.
+func (d *DOPC) venueSchedule(ctx context.Context, venueSlug string, staticResponse *models.VenueStaticResponse) (*availability.Schedule, bool) {
+	if d.schedules != nil {
+		if schedule, ok := d.schedules.Schedule(venueSlug); ok {
+			return schedule, true
+		}
+	}
+	venue := staticResponse.VenueRaw
+	if len(venue.OpeningHours) == 0 {
+		return nil, false
+	}
+	schedule, err := availability.ParseSchedule(venue.Timezone, venue.OpeningHours)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "ignoring invalid opening hours",
+			slog.String("venue_slug", venueSlug),
+			slog.String("error", err.Error()),
+		)
+		return nil, false
+	}
+	return schedule, true
+}
+
+// matchZone returns the delivery zone of the venue containing the user location, if zones are configured.
+func (d *DOPC) matchZone(orderInfo *models.OrderInfo) (zones.Zone, bool) {
+	if d.zones == nil {
+		return zones.Zone{}, false
+	}
+	return d.zones.Match(orderInfo.Slug, orderInfo.Lat, orderInfo.Lon)
+}
+
+// endSpan marks the span as failed when err is set and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"backend-wolt-go/internal/mockvenue"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/service"
+	"context"
+	"testing"
+	"time"
+)
+
+// fixedVenueProvider returns the same venue for every slug, without any mocking overhead.
+type fixedVenueProvider struct {
+	static  *models.VenueStaticResponse
+	dynamic *models.VenueDynamicResponse
+}
+
+func (p fixedVenueProvider) GetVenueInformation(context.Context, string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	return p.static, p.dynamic, nil
+}
+
+// benchmarkOrder is 700 m from the test venue, in its second distance range.
+var benchmarkOrder = &models.OrderInfo{Slug: "venue", Lat: 60.17546, Lon: 24.93545, CartValue: 800}
+
+// BenchmarkDOPC_CalculateDeliveryFee measures the price calculation alone.
+func BenchmarkDOPC_CalculateDeliveryFee(b *testing.B) {
+	staticResp, dynamicResp := decodeVenue(b, testStaticJSON, testDynamicJSON)
+	dopc := NewDOPC(fixedVenueProvider{static: staticResp, dynamic: dynamicResp})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := dopc.CalculateDeliveryFee(ctx, benchmarkOrder); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkDOPC_CalculateDeliveryFee_VenueAPI measures the price calculation including the
+// venue API calls over HTTP to a local mock, with and without the HTTP cache.
+func BenchmarkDOPC_CalculateDeliveryFee_VenueAPI(b *testing.B) {
+	api := mockvenue.New(map[string]mockvenue.Venue{
+		"venue": {Static: []byte(testStaticJSON), Dynamic: []byte(testDynamicJSON)},
+	})
+	server := mockvenue.NewServer(b, api)
+
+	for _, bm := range []struct {
+		name string
+		opts []service.Option
+	}{
+		{name: "NoCache"},
+		{name: "HTTPCache", opts: []service.Option{service.WithHTTPCache(time.Minute, 0)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			dopc := NewDOPC(service.NewVenueProvider(server.URL, bm.opts...))
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := dopc.CalculateDeliveryFee(ctx, benchmarkOrder); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
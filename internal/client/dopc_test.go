@@ -2,12 +2,28 @@ package client
 
 import (
 	
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/feerules"
 	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/overrides"
+	"backend-wolt-go/internal/quotelog"
+	"backend-wolt-go/internal/utils"
+	"backend-wolt-go/internal/zones"
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // ------------------------------------------------------------
@@ -49,86 +65,21 @@ func TestDOPC_CalculateDeliveryFee(t *testing.T) {
 		CartValue: 1000,     // e.g. 10 euros in cents
 	}
 
-	// Define what static venue data the mock should return
-	staticResp := &models.VenueStaticResponse{
-		VenueRaw: &struct {
-			Location struct {
-				Coordinates []float64 `json:"coordinates"`
-			} `json:"location"`
-		}{
-			Location: struct {
-				Coordinates []float64 `json:"coordinates"`
-			}{
-				// Typically: Coordinates[0] = longitude, Coordinates[1] = latitude
-				Coordinates: []float64{24.93545, 60.16952},
-			},
-		},
-	}
-
-	// Define what dynamic venue data the mock should return
-	dynamicResp := &models.VenueDynamicResponse{
-		VenueRaw: &struct {
-			DeliverySpecs struct {
-				OrderMinimumNoSurcharge int `json:"order_minimum_no_surcharge"`
-				DeliveryPricing         struct {
-					BasePrice      int `json:"base_price"`
-					DistanceRanges []struct {
-						Min int     `json:"min"`
-						Max int     `json:"max"`
-						A   int     `json:"a"`
-						B   float64 `json:"b"`
-					} `json:"distance_ranges"`
-				} `json:"delivery_pricing"`
-			} `json:"delivery_specs"`
-		}{
-			DeliverySpecs: struct {
-				OrderMinimumNoSurcharge int `json:"order_minimum_no_surcharge"`
-				DeliveryPricing         struct {
-					BasePrice      int `json:"base_price"`
-					DistanceRanges []struct {
-						Min int     `json:"min"`
-						Max int     `json:"max"`
-						A   int     `json:"a"`
-						B   float64 `json:"b"`
-					} `json:"distance_ranges"`
-				} `json:"delivery_pricing"`
-			}{
-				OrderMinimumNoSurcharge: 1000, // No surcharge if cart >= 1000
-				DeliveryPricing: struct {
-					BasePrice      int `json:"base_price"`
-					DistanceRanges []struct {
-						Min int     `json:"min"`
-						Max int     `json:"max"`
-						A   int     `json:"a"`
-						B   float64 `json:"b"`
-					} `json:"distance_ranges"`
-				}{
-					BasePrice: 300, // e.g. 3 euros in cents
-					DistanceRanges: []struct {
-						Min int     `json:"min"`
-						Max int     `json:"max"`
-						A   int     `json:"a"`
-						B   float64 `json:"b"`
-					}{
-						// first range
-						{
-							Min: 0,
-							Max: 2000,
-							A:   100,
-							B:   0.05,
-						},
-						// second range
-						{
-							Min: 2001,
-							Max: 5000,
-							A:   200,
-							B:   0.06,
-						},
-					},
-				},
-			},
-		},
-	}
+	// Define what static and dynamic venue data the mock should return.
+	// Coordinates are [longitude, latitude]; prices are in cents.
+	staticResp, dynamicResp := decodeVenue(t,
+		`{"venue_raw": {"location": {"coordinates": [24.93545, 60.16952]}}}`,
+		`{"venue_raw": {"delivery_specs": {
+			"order_minimum_no_surcharge": 1000,
+			"delivery_pricing": {
+				"base_price": 300,
+				"distance_ranges": [
+					{"min": 0, "max": 2000, "a": 100, "b": 0.05},
+					{"min": 2001, "max": 5000, "a": 200, "b": 0.06}
+				]
+			}
+		}}}`,
+	)
 
 	// 3. Setup the mock to return the above static/dynamic responses
 	mockProvider.
@@ -164,3 +115,534 @@ func TestDOPC_CalculateDeliveryFee(t *testing.T) {
 	// from the user, we at least verify distance is > 0
 	assert.True(t, result.Delivery.Distance > 0, "Distance should be greater than 0")
 }
+
+// ------------------------------------------------------------
+// 3. Recorder notifications
+// ------------------------------------------------------------
+type fakeRecorder struct {
+	quotes     [][2]int
+	outOfRange []string
+}
+
+func (f *fakeRecorder) ObserveQuote(distance, fee int) {
+	f.quotes = append(f.quotes, [2]int{distance, fee})
+}
+
+func (f *fakeRecorder) ObserveOutOfRange(venueSlug string) {
+	f.outOfRange = append(f.outOfRange, venueSlug)
+}
+
+// decodeVenue builds venue responses from their JSON representation.
+func decodeVenue(t testing.TB, staticJSON, dynamicJSON string) (*models.VenueStaticResponse, *models.VenueDynamicResponse) {
+	t.Helper()
+	staticResp := &models.VenueStaticResponse{}
+	dynamicResp := &models.VenueDynamicResponse{}
+	if err := json.Unmarshal([]byte(staticJSON), staticResp); err != nil {
+		t.Fatalf("invalid static fixture: %v", err)
+	}
+	if err := json.Unmarshal([]byte(dynamicJSON), dynamicResp); err != nil {
+		t.Fatalf("invalid dynamic fixture: %v", err)
+	}
+	return staticResp, dynamicResp
+}
+
+const (
+	testStaticJSON  = `{"venue_raw": {"location": {"coordinates": [24.93545, 60.16952]}}}`
+	testDynamicJSON = `{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000, "delivery_pricing": {"base_price": 190, "distance_ranges": [{"min": 0, "max": 500, "a": 0, "b": 0}, {"min": 500, "max": 1000, "a": 100, "b": 1}, {"min": 1000, "max": 0, "a": 0, "b": 0}]}}}}`
+)
+
+func TestDOPC_CalculateDeliveryFee_Recorder(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	recorder := &fakeRecorder{}
+	dopc := NewDOPC(mockProvider, WithRecorder(recorder))
+
+	// A user next to the venue is inside the first range.
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int{{result.Delivery.Distance, result.Delivery.Fee}}, recorder.quotes)
+
+	// A user several kilometres away is out of range.
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.2, Lon: 25.0, CartValue: 1000})
+	assert.ErrorIs(t, err, utils.ErrDeliveryNotPossible)
+	assert.Equal(t, []string{"venue"}, recorder.outOfRange)
+}
+
+// ------------------------------------------------------------
+// 4. Tracing
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_Spans(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	_, err := NewDOPC(mockProvider).CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+
+	ended := spans.Ended()
+	if assert.Len(t, ended, 2) {
+		assert.Equal(t, "DOPC.calculate", ended[0].Name())
+		assert.Equal(t, "DOPC.CalculateDeliveryFee", ended[1].Name())
+		assert.Equal(t, ended[1].SpanContext().SpanID(), ended[0].Parent().SpanID())
+		assert.Contains(t, ended[0].Attributes(), attribute.Int("delivery.range.index", 0))
+		assert.Contains(t, ended[1].Attributes(), attribute.String("venue.slug", "venue"))
+	}
+}
+
+// ------------------------------------------------------------
+// 5. Stale venue data
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_Stale(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	dynamicResp.Stale = true
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	result, err := NewDOPC(mockProvider).CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Stale)
+}
+
+// ------------------------------------------------------------
+// 6. Delivery zones
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_Zones(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	// The zone covers a location several kilometres away, beyond the distance ranges.
+	store, err := zones.Load(models.DeliveryZonesConfig{
+		Venues: map[string][]models.ZoneConfig{
+			"venue": {{
+				Name: "north",
+				A:    300,
+				B:    0.1,
+				Geometry: map[string]any{
+					"type":        "Polygon",
+					"coordinates": []any{[]any{[]any{24.99, 60.19}, []any{25.01, 60.19}, []any{25.01, 60.21}, []any{24.99, 60.21}, []any{24.99, 60.19}}},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("invalid zones: %v", err)
+	}
+	sink := &fakeQuoteSink{}
+	dopc := NewDOPC(mockProvider, WithZones(store), WithQuoteLog(sink))
+
+	// Inside the zone, the fee follows the zone components, which are recorded in the quote.
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.2, Lon: 25.0, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, "north", result.Delivery.Zone)
+	assert.Equal(t, 190+300+int(0.1*float64(result.Delivery.Distance)/10), result.Delivery.Fee)
+	assert.Equal(t, &quotelog.MatchedRange{Zone: "north", DistanceRange: models.DistanceRange{A: 300, B: 0.1}}, sink.events[0].Range)
+
+	// Outside of every zone, the distance ranges apply.
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Delivery.Zone)
+	assert.Equal(t, 190, result.Delivery.Fee)
+
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.25, Lon: 25.0, CartValue: 1000})
+	assert.ErrorIs(t, err, utils.ErrDeliveryNotPossible)
+}
+
+// ------------------------------------------------------------
+// 7. Excluded areas
+// ------------------------------------------------------------
+type fakeExclusions struct {
+	venueSlug string
+}
+
+func (f fakeExclusions) Check(venueSlug string, lat, lon float64) error {
+	if venueSlug == f.venueSlug {
+		return &exclusions.NotServiceableError{Reason: "airport", ExclusionID: "hel"}
+	}
+	return nil
+}
+
+func TestDOPC_CalculateDeliveryFee_Excluded(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+	dopc := NewDOPC(mockProvider, WithExclusions(fakeExclusions{venueSlug: "excluded-venue"}))
+
+	// Excluded addresses are rejected without fetching the venue data.
+	_, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "excluded-venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	var notServiceable *exclusions.NotServiceableError
+	if assert.ErrorAs(t, err, &notServiceable) {
+		assert.Equal(t, "airport", notServiceable.Reason)
+	}
+	mockProvider.AssertNotCalled(t, "GetVenueInformation", mock.Anything, "excluded-venue")
+
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+}
+
+// ------------------------------------------------------------
+// 8. Opening hours and delivery availability
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_Availability(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	mondayMorning := time.Date(2030, time.January, 7, 8, 0, 0, 0, helsinki)
+	mondayNoon := time.Date(2030, time.January, 7, 12, 0, 0, 0, helsinki)
+
+	openingHours := `{"venue_raw": {"location": {"coordinates": [24.93545, 60.16952]}, "timezone": "Europe/Helsinki", "opening_hours": {"monday": ["10:00-22:00"]}}}`
+	disabledDynamicJSON := `{"venue_raw": {"delivery_specs": {"delivery_enabled": false, "order_minimum_no_surcharge": 1000, "delivery_pricing": {"base_price": 190, "distance_ranges": [{"min": 0, "max": 1000, "a": 0, "b": 0}, {"min": 1000, "max": 0, "a": 0, "b": 0}]}}}}`
+
+	tests := []struct {
+		name         string
+		staticJSON   string
+		dynamicJSON  string
+		schedules    ScheduleSource
+		deliveryTime time.Time
+		wantReason   string
+		wantNext     time.Time
+	}{
+		{name: "NoOpeningHours", staticJSON: testStaticJSON, dynamicJSON: testDynamicJSON},
+		{name: "Closed", staticJSON: openingHours, dynamicJSON: testDynamicJSON, wantReason: availability.ReasonClosed, wantNext: time.Date(2030, time.January, 7, 10, 0, 0, 0, helsinki)},
+		{name: "ScheduledWhenOpen", staticJSON: openingHours, dynamicJSON: testDynamicJSON, deliveryTime: mondayNoon},
+		{name: "DeliveryDisabled", staticJSON: testStaticJSON, dynamicJSON: disabledDynamicJSON, wantReason: availability.ReasonDeliveryDisabled},
+		{name: "ScheduledWhileDisabled", staticJSON: testStaticJSON, dynamicJSON: disabledDynamicJSON, deliveryTime: mondayNoon},
+		{
+			name:        "ConfiguredSchedule",
+			staticJSON:  openingHours,
+			dynamicJSON: testDynamicJSON,
+			schedules:   mustLoadSchedules(t, map[string][]string{"monday": {"06:00-09:00"}}),
+		},
+		{
+			name:         "ConfiguredScheduleClosed",
+			staticJSON:   openingHours,
+			dynamicJSON:  testDynamicJSON,
+			schedules:    mustLoadSchedules(t, map[string][]string{"monday": {"06:00-09:00"}}),
+			deliveryTime: mondayNoon,
+			wantReason:   availability.ReasonClosed,
+			wantNext:     time.Date(2030, time.January, 14, 6, 0, 0, 0, helsinki),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staticResp, dynamicResp := decodeVenue(t, tt.staticJSON, tt.dynamicJSON)
+			mockProvider := new(mockVenueProvider)
+			mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+			var opts []Option
+			if tt.schedules != nil {
+				opts = append(opts, WithSchedules(tt.schedules))
+			}
+			dopc := NewDOPC(mockProvider, opts...)
+			dopc.now = func() time.Time { return mondayMorning }
+
+			_, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, DeliveryTime: tt.deliveryTime})
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var notDelivering *availability.NotDeliveringError
+			if assert.ErrorAs(t, err, &notDelivering) {
+				assert.Equal(t, tt.wantReason, notDelivering.Reason)
+				assert.True(t, tt.wantNext.Equal(notDelivering.NextAvailableAt), "next available at %v, want %v", notDelivering.NextAvailableAt, tt.wantNext)
+			}
+		})
+	}
+}
+
+// mustLoadSchedules configures the opening hours of "venue" in Helsinki.
+func mustLoadSchedules(t *testing.T, hours map[string][]string) availability.Schedules {
+	t.Helper()
+	schedules, err := availability.LoadSchedules(models.AvailabilityConfig{Venues: map[string]models.ScheduleConfig{
+		"venue": {Timezone: "Europe/Helsinki", OpeningHours: hours},
+	}})
+	if err != nil {
+		t.Fatalf("invalid schedules: %v", err)
+	}
+	return schedules
+}
+
+// ------------------------------------------------------------
+// 9. Scheduled orders and delivery slots
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_FeeRules(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	rules, err := feerules.Load([]models.FeeRuleConfig{{
+		Name:       "monday-surge",
+		Timezone:   "Europe/Helsinki",
+		Hours:      map[string][]string{"monday": {"08:00-10:00"}},
+		Multiplier: 2,
+	}})
+	if err != nil {
+		t.Fatalf("invalid fee rules: %v", err)
+	}
+	dopc := NewDOPC(mockProvider, WithFeeRules(rules))
+	dopc.now = func() time.Time { return time.Date(2030, time.January, 7, 7, 0, 0, 0, time.UTC) } // 09:00 in Helsinki.
+
+	// The surge applies now...
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, 380, result.Delivery.Fee)
+	assert.Equal(t, []string{"monday-surge"}, result.Delivery.Rules)
+	assert.Equal(t, 1380, result.TotalPrice)
+	assert.Nil(t, result.ScheduledFor)
+
+	// ...but not to an order scheduled for later in the day.
+	scheduledFor := time.Date(2030, time.January, 7, 12, 0, 0, 0, time.UTC)
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, DeliveryTime: scheduledFor})
+	assert.NoError(t, err)
+	assert.Equal(t, 190, result.Delivery.Fee)
+	assert.Empty(t, result.Delivery.Rules)
+	if assert.NotNil(t, result.ScheduledFor) {
+		assert.True(t, scheduledFor.Equal(*result.ScheduledFor))
+	}
+}
+
+func TestDOPC_QuoteSlots(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t,
+		`{"venue_raw": {"location": {"coordinates": [24.93545, 60.16952]}, "timezone": "UTC", "opening_hours": {"monday": ["10:00-12:00"]}}}`,
+		testDynamicJSON,
+	)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	rules, err := feerules.Load([]models.FeeRuleConfig{{Name: "lunch", Hours: map[string][]string{"monday": {"11:00-12:00"}}, Surcharge: 50}})
+	if err != nil {
+		t.Fatalf("invalid fee rules: %v", err)
+	}
+	dopc := NewDOPC(mockProvider, WithFeeRules(rules))
+
+	slot := func(hour int) time.Time { return time.Date(2030, time.January, 7, hour, 0, 0, 0, time.UTC) }
+	quotes, err := dopc.QuoteSlots(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000}, []time.Time{slot(9), slot(10), slot(11), slot(12)})
+
+	// The venue data is fetched once, and the slots outside of the opening hours are left out.
+	assert.NoError(t, err)
+	mockProvider.AssertNumberOfCalls(t, "GetVenueInformation", 1)
+	if assert.Len(t, quotes, 2) {
+		assert.True(t, slot(10).Equal(*quotes[0].ScheduledFor))
+		assert.Equal(t, 190, quotes[0].Delivery.Fee)
+		assert.True(t, slot(11).Equal(*quotes[1].ScheduledFor))
+		assert.Equal(t, 240, quotes[1].Delivery.Fee)
+	}
+
+	// Errors other than the venue not delivering fail the whole request.
+	_, err = dopc.QuoteSlots(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.25, Lon: 25.0, CartValue: 1000}, []time.Time{slot(10)})
+	assert.ErrorIs(t, err, utils.ErrDeliveryNotPossible)
+}
+
+// ------------------------------------------------------------
+// 10. Delivery time estimates
+// ------------------------------------------------------------
+type fakeEstimator struct{}
+
+func (fakeEstimator) Estimate(_ context.Context, _ string, distance int, _ string) models.DeliveryEstimate {
+	return models.DeliveryEstimate{Expected: distance, Min: distance - 1, Max: distance + 1}
+}
+
+func TestDOPC_CalculateDeliveryFee_Estimate(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+	dopc := NewDOPC(mockProvider, WithEstimator(fakeEstimator{}))
+
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	if assert.NotNil(t, result.EstimatedDeliveryMinutes) {
+		assert.Equal(t, result.Delivery.Distance, result.EstimatedDeliveryMinutes.Expected)
+	}
+
+	// Scheduled orders are delivered at their scheduled time.
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, DeliveryTime: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Nil(t, result.EstimatedDeliveryMinutes)
+}
+
+// ------------------------------------------------------------
+// 11. Courier modes
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_CourierModes(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+	modes := couriers.Modes{
+		{Name: "bike", DistanceRanges: []models.DistanceRange{{Min: 0, Max: 3000, A: 100, B: 0}}},
+		{Name: "walking", DistanceRanges: []models.DistanceRange{{Min: 0, Max: 1000, A: 50, B: 0}}},
+	}
+	dopc := NewDOPC(mockProvider, WithCourierModes(modes))
+
+	// The cheapest mode serving the distance is picked.
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, "walking", result.Delivery.Mode)
+	assert.Equal(t, 240, result.Delivery.Fee)
+
+	// A requested mode is used even if another one is cheaper.
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, CourierMode: "bike"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bike", result.Delivery.Mode)
+	assert.Equal(t, 290, result.Delivery.Fee)
+
+	// The fee of the mode is added to the fee of the venue distance range: 190 + 100 + 69 for the
+	// venue at 697 meters, plus 50 for walking.
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.16952, Lon: 24.94805, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, "walking", result.Delivery.Mode)
+	assert.Equal(t, 697, result.Delivery.Distance)
+	assert.Equal(t, 409, result.Delivery.Fee)
+
+	// The venue does not deliver beyond its last distance range, even where a mode would.
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.16952, Lon: 24.962, CartValue: 1000, CourierMode: "bike"})
+	assert.ErrorIs(t, err, utils.ErrDeliveryNotPossible)
+
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, CourierMode: "car"})
+	var unavailable *couriers.ModeUnavailableError
+	if assert.ErrorAs(t, err, &unavailable) {
+		assert.Equal(t, "car", unavailable.Mode)
+	}
+
+	// Without configured modes, requesting one fails and the venue distance ranges apply otherwise.
+	dopc = NewDOPC(mockProvider)
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, CourierMode: "bike"})
+	assert.ErrorAs(t, err, &unavailable)
+
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Delivery.Mode)
+	assert.Equal(t, 190, result.Delivery.Fee)
+}
+
+// ------------------------------------------------------------
+// 12. Pricing overrides
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_Overrides(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	now := time.Date(2030, time.January, 7, 12, 0, 0, 0, time.UTC)
+	launch := now.Add(time.Hour)
+	basePrice, orderMinimum := 50, 2000
+	store := overrides.NewStore()
+	err := store.Put(overrides.Override{
+		VenueSlug:               "venue",
+		DeliveryPricing:         &overrides.Pricing{BasePrice: &basePrice, DistanceRanges: []models.DistanceRange{{Min: 0, Max: 10000, A: 0, B: 0}, {Min: 10000, Max: 0}}},
+		OrderMinimumNoSurcharge: &orderMinimum,
+		StartsAt:                &launch,
+	}, "")
+	if err != nil {
+		t.Fatalf("invalid override: %v", err)
+	}
+	dopc := NewDOPC(mockProvider, WithOverrides(store))
+	dopc.now = func() time.Time { return now }
+
+	// Before its start, the override does not apply.
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, 190, result.Delivery.Fee)
+	assert.Equal(t, 0, result.SmallOrderSurcharge)
+
+	// Orders delivered once it has started are priced with the overridden values.
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000, DeliveryTime: launch})
+	assert.NoError(t, err)
+	assert.Equal(t, 50, result.Delivery.Fee)
+	assert.Equal(t, 1000, result.SmallOrderSurcharge)
+
+	// The overridden distance ranges reach further than the upstream ones.
+	result, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.2, Lon: 25.0, CartValue: 1000, DeliveryTime: launch})
+	assert.NoError(t, err)
+	assert.Equal(t, 50, result.Delivery.Fee)
+
+	// The upstream data is left untouched.
+	assert.Equal(t, 190, dynamicResp.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice)
+	assert.Equal(t, 1000, dynamicResp.VenueRaw.DeliverySpecs.OrderMinimumNoSurcharge)
+}
+
+// ------------------------------------------------------------
+// 13. Quote log
+// ------------------------------------------------------------
+type fakeQuoteSink struct {
+	events []quotelog.Event
+	err    error
+}
+
+func (f *fakeQuoteSink) Write(_ context.Context, event quotelog.Event) error {
+	f.events = append(f.events, event)
+	return f.err
+}
+
+func TestDOPC_CalculateDeliveryFee_QuoteLog(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+	mockProvider.On("GetVenueInformation", mock.Anything, "unknown-venue").Return(nil, nil, errors.New("venue not found"))
+	sink := &fakeQuoteSink{}
+	dopc := NewDOPC(mockProvider, WithQuoteLog(sink))
+
+	result, err := dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.QuoteID)
+
+	// A user several kilometres away is out of range, and one of an unknown venue gets no data.
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.2, Lon: 25.0, CartValue: 1000})
+	assert.ErrorIs(t, err, utils.ErrDeliveryNotPossible)
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "unknown-venue", Lat: 60.2, Lon: 25.0, CartValue: 1000})
+	assert.Error(t, err)
+
+	if !assert.Len(t, sink.events, 3) {
+		return
+	}
+	success := sink.events[0]
+	assert.Equal(t, result.QuoteID, success.QuoteID)
+	assert.Equal(t, "venue", success.Order.Slug)
+	assert.Equal(t, quotelog.VenueVersion(staticResp, dynamicResp), success.VenueVersion)
+	assert.Equal(t, &quotelog.MatchedRange{Index: 0, DistanceRange: models.DistanceRange{Min: 0, Max: 500}}, success.Range)
+	assert.Equal(t, &result, success.Result)
+	assert.Empty(t, success.Error)
+
+	outOfRange := sink.events[1]
+	assert.Nil(t, outOfRange.Result)
+	assert.Nil(t, outOfRange.Range)
+	assert.Greater(t, outOfRange.Distance, 1000)
+	assert.Equal(t, utils.ErrDeliveryNotPossible.Error(), outOfRange.Error)
+
+	assert.Empty(t, sink.events[2].VenueVersion)
+	assert.Equal(t, "venue not found", sink.events[2].Error)
+
+	// A failing quote log does not fail the calculation.
+	sink.err = errors.New("disk full")
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	// With courier modes, the matched range of the fee table of the picked mode is recorded.
+	sink = &fakeQuoteSink{}
+	modes := couriers.Modes{{Name: "bike", DistanceRanges: []models.DistanceRange{{Min: 0, Max: 100, A: 0, B: 0}, {Min: 100, Max: 3000, A: 100, B: 0}}}}
+	dopc = NewDOPC(mockProvider, WithQuoteLog(sink), WithCourierModes(modes))
+	_, err = dopc.CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, &quotelog.MatchedRange{Index: 1, Mode: "bike", DistanceRange: models.DistanceRange{Min: 100, Max: 3000, A: 100}}, sink.events[0].Range)
+}
+
+// ------------------------------------------------------------
+// 14. Invalid venue location
+// ------------------------------------------------------------
+func TestDOPC_CalculateDeliveryFee_InvalidLocation(t *testing.T) {
+	staticResp, dynamicResp := decodeVenue(t, testStaticJSON, testDynamicJSON)
+	staticResp.VenueRaw.Location.Coordinates = []float64{24.93}
+	mockProvider := new(mockVenueProvider)
+	mockProvider.On("GetVenueInformation", mock.Anything, "venue").Return(staticResp, dynamicResp, nil)
+
+	_, err := NewDOPC(mockProvider).CalculateDeliveryFee(context.Background(), &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000})
+
+	assert.ErrorIs(t, err, ErrInvalidVenueLocation)
+}
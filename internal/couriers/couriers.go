@@ -0,0 +1,95 @@
+package couriers
+
+import (
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/utils"
+	"errors"
+	"fmt"
+)
+
+// ReasonModeUnavailable is the reason code reported for a ModeUnavailableError.
+const ReasonModeUnavailable = "courier_mode_unavailable"
+
+// ModeUnavailableError is returned when the requested courier mode does not exist or does not
+// serve the distance.
+type ModeUnavailableError struct {
+	Mode string
+}
+
+func (e *ModeUnavailableError) Error() string {
+	return fmt.Sprintf("courier mode %q is not available for this delivery", e.Mode)
+}
+
+// Mode is a courier transport mode with its fee table, charged on top of the fee of the venue.
+type Mode struct {
+	Name           string
+	DistanceRanges []models.DistanceRange
+}
+
+// Choice is the courier mode picked for an order, the range of its fee table matching the
+// distance and the delivery fee, including the fee of the venue.
+type Choice struct {
+	Mode  string
+	Fee   int
+	Index int                  // Index of the matched range in the fee table of the mode.
+	Range models.DistanceRange // Matched range of the fee table of the mode.
+}
+
+// Modes is the list of courier modes. On equal fees, the mode listed first is picked.
+type Modes []Mode
+
+// Load builds the courier modes of the configuration.
+func Load(configs []models.CourierModeConfig) (Modes, error) {
+	modes := make(Modes, 0, len(configs))
+	seen := make(map[string]bool, len(configs))
+	for i, config := range configs {
+		if config.Name == "" {
+			return nil, fmt.Errorf("courier mode %d has no name", i)
+		}
+		if seen[config.Name] {
+			return nil, fmt.Errorf("courier mode %q is defined twice", config.Name)
+		}
+		if len(config.DistanceRanges) == 0 {
+			return nil, fmt.Errorf("courier mode %q has no distance ranges", config.Name)
+		}
+		seen[config.Name] = true
+		modes = append(modes, Mode{Name: config.Name, DistanceRanges: config.DistanceRanges})
+	}
+	return modes, nil
+}
+
+// Choose returns the cheapest mode serving the distance, or the requested mode when it is set,
+// with its fee table added to venueFee, the fee computed from the distance ranges of the venue.
+// It returns a *ModeUnavailableError if the requested mode does not serve the distance, and
+// utils.ErrDeliveryNotPossible if no mode does.
+func (m Modes) Choose(distance, venueFee int, requested string) (Choice, error) {
+	var (
+		best  Choice
+		found bool
+	)
+	for _, mode := range m {
+		if requested != "" && mode.Name != requested {
+			continue
+		}
+		fee, err := utils.CalculateDeliveryFee(distance, venueFee, mode.DistanceRanges)
+		if errors.Is(err, utils.ErrDeliveryNotPossible) {
+			continue
+		}
+		if err != nil {
+			return Choice{}, err
+		}
+		if !found || fee < best.Fee {
+			i, _ := utils.FindDistanceRange(distance, mode.DistanceRanges)
+			best, found = Choice{Mode: mode.Name, Fee: fee, Index: i, Range: mode.DistanceRanges[i]}, true
+		}
+	}
+
+	switch {
+	case found:
+		return best, nil
+	case requested != "":
+		return Choice{}, &ModeUnavailableError{Mode: requested}
+	default:
+		return Choice{}, utils.ErrDeliveryNotPossible
+	}
+}
@@ -0,0 +1,81 @@
+package couriers
+
+import (
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModes_Choose(t *testing.T) {
+	modes, err := Load([]models.CourierModeConfig{
+		{
+			Name:           "walking",
+			DistanceRanges: []models.DistanceRange{{Min: 0, Max: 1000, A: 50, B: 0}, {Min: 1000, Max: 0, A: 0, B: 0}},
+		},
+		{
+			Name:           "bike",
+			DistanceRanges: []models.DistanceRange{{Min: 0, Max: 3000, A: 100, B: 0}, {Min: 3000, Max: 0, A: 0, B: 0}},
+		},
+		{
+			Name:           "car",
+			DistanceRanges: []models.DistanceRange{{Min: 0, Max: 10000, A: 100, B: 1}, {Min: 10000, Max: 0, A: 0, B: 0}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		distance  int
+		requested string
+		want      Choice
+		wantErr   error
+	}{
+		{"Cheapest", 500, "", Choice{Mode: "walking", Fee: 240, Range: models.DistanceRange{Min: 0, Max: 1000, A: 50}}, nil},
+		{"BeyondWalking", 1500, "", Choice{Mode: "bike", Fee: 290, Range: models.DistanceRange{Min: 0, Max: 3000, A: 100}}, nil},
+		{"OnlyModeInRange", 5000, "", Choice{Mode: "car", Fee: 790, Range: models.DistanceRange{Min: 0, Max: 10000, A: 100, B: 1}}, nil},
+		{"Requested", 500, "car", Choice{Mode: "car", Fee: 340, Range: models.DistanceRange{Min: 0, Max: 10000, A: 100, B: 1}}, nil},
+		{"RequestedOutOfRange", 5000, "bike", Choice{}, &ModeUnavailableError{Mode: "bike"}},
+		{"RequestedUnknown", 500, "drone", Choice{}, &ModeUnavailableError{Mode: "drone"}},
+		{"NoModeInRange", 20000, "", Choice{}, utils.ErrDeliveryNotPossible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choice, err := modes.Choose(tt.distance, 190, tt.requested)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, choice)
+		})
+	}
+}
+
+func TestModes_Choose_Tie(t *testing.T) {
+	ranges := []models.DistanceRange{{Min: 0, Max: 5000, A: 0, B: 0}}
+	modes := Modes{{Name: "bike", DistanceRanges: ranges}, {Name: "car", DistanceRanges: ranges}}
+
+	choice, err := modes.Choose(1000, 190, "")
+	assert.NoError(t, err)
+	assert.Equal(t, Choice{Mode: "bike", Fee: 190, Range: ranges[0]}, choice)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	ranges := []models.DistanceRange{{Min: 0, Max: 0, A: 0, B: 0}}
+	tests := []struct {
+		name    string
+		configs []models.CourierModeConfig
+	}{
+		{"MissingName", []models.CourierModeConfig{{DistanceRanges: ranges}}},
+		{"Duplicate", []models.CourierModeConfig{{Name: "bike", DistanceRanges: ranges}, {Name: "bike", DistanceRanges: ranges}}},
+		{"NoDistanceRanges", []models.CourierModeConfig{{Name: "bike"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.configs)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package eta
+
+import (
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// QueueLoad is a signal of how busy a venue is, such as the orders waiting in its kitchen.
+type QueueLoad interface {
+	// QueueTime returns the extra time orders of the venue currently wait before being prepared.
+	QueueTime(ctx context.Context, venueSlug string) (time.Duration, error)
+}
+
+// StaticQueueLoad is a QueueLoad with a fixed waiting time per venue slug.
+type StaticQueueLoad map[string]time.Duration
+
+// QueueTime returns the configured waiting time of the venue, zero if none.
+func (s StaticQueueLoad) QueueTime(_ context.Context, venueSlug string) (time.Duration, error) {
+	return s[venueSlug], nil
+}
+
+// Profile is the speed of a kind of courier over the distances it covers.
+type Profile struct {
+	Name        string
+	Speed       float64 // Average speed in meters per second.
+	MaxDistance int     // Longest distance in meters covered by the profile; 0 for any distance.
+}
+
+// covers reports whether the profile covers the distance.
+func (p Profile) covers(distance int) bool {
+	return p.MaxDistance == 0 || distance <= p.MaxDistance
+}
+
+// defaultProfiles are used when the configuration lists none.
+var defaultProfiles = []Profile{
+	{Name: "walking", Speed: 5 / 3.6, MaxDistance: 1000},
+	{Name: "bike", Speed: 15 / 3.6, MaxDistance: 5000},
+	{Name: "car", Speed: 25 / 3.6},
+}
+
+// Option is a function that configures an Estimator.
+type Option func(*Estimator)
+
+// WithQueueLoad adds the waiting time reported by the queue load signal to the estimates.
+func WithQueueLoad(queue QueueLoad) Option {
+	return func(e *Estimator) {
+		e.queue = queue
+	}
+}
+
+// Estimator estimates the delivery time of an order: the preparation time of the venue, the
+// waiting time reported by its queue load and the travel time of the courier over the distance.
+// It is safe for concurrent use.
+type Estimator struct {
+	preparationTime       time.Duration
+	venuePreparationTimes map[string]time.Duration
+	profiles              []Profile
+	spread                float64 // Relative width of the min/max bounds.
+	queue                 QueueLoad
+}
+
+// NewEstimator creates an Estimator from the configuration. The preparation time defaults to
+// 10 minutes, the spread to 0.2, and the profiles to walking, bike and car couriers.
+func NewEstimator(config models.ETAConfig, opts ...Option) (*Estimator, error) {
+	e := &Estimator{
+		preparationTime:       config.PreparationTime,
+		venuePreparationTimes: config.VenuePreparationTimes,
+		spread:                config.Spread,
+		profiles:              defaultProfiles,
+	}
+	if e.preparationTime <= 0 {
+		e.preparationTime = 10 * time.Minute
+	}
+	if e.spread <= 0 {
+		e.spread = 0.2
+	}
+	if e.spread >= 1 {
+		return nil, fmt.Errorf("eta spread must be below 1, got %v", e.spread)
+	}
+	if len(config.Profiles) > 0 {
+		e.profiles = make([]Profile, 0, len(config.Profiles))
+		for _, profile := range config.Profiles {
+			if profile.SpeedKMH <= 0 {
+				return nil, fmt.Errorf("courier profile %q: speed must be positive", profile.Name)
+			}
+			e.profiles = append(e.profiles, Profile{Name: profile.Name, Speed: profile.SpeedKMH / 3.6, MaxDistance: profile.MaxDistance})
+		}
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// profile returns the profile named after the courier mode if there is one, and otherwise the
+// first profile covering the distance, or the last one if none does.
+func (e *Estimator) profile(distance int, mode string) Profile {
+	if mode != "" {
+		for _, profile := range e.profiles {
+			if profile.Name == mode {
+				return profile
+			}
+		}
+	}
+	for _, profile := range e.profiles {
+		if profile.covers(distance) {
+			return profile
+		}
+	}
+	return e.profiles[len(e.profiles)-1]
+}
+
+// Estimate returns the estimated delivery time of an order of the venue over the distance in
+// meters, travelled by the courier mode if it is set. A failing queue load signal is logged and
+// left out of the estimate.
+func (e *Estimator) Estimate(ctx context.Context, venueSlug string, distance int, mode string) models.DeliveryEstimate {
+	preparationTime, ok := e.venuePreparationTimes[venueSlug]
+	if !ok {
+		preparationTime = e.preparationTime
+	}
+
+	var queueTime time.Duration
+	if e.queue != nil {
+		var err error
+		queueTime, err = e.queue.QueueTime(ctx, venueSlug)
+		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "failed to get venue queue load",
+				slog.String("venue_slug", venueSlug),
+				slog.String("error", err.Error()),
+			)
+			queueTime = 0
+		}
+	}
+
+	travelTime := time.Duration(float64(distance) / e.profile(distance, mode).Speed * float64(time.Second))
+	expected := (preparationTime + queueTime + travelTime).Minutes()
+
+	return models.DeliveryEstimate{
+		Expected: int(math.Round(expected)),
+		Min:      max(1, int(math.Floor(expected*(1-e.spread)))),
+		Max:      int(math.Ceil(expected * (1 + e.spread))),
+	}
+}
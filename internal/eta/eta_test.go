@@ -0,0 +1,75 @@
+package eta
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingQueue is a QueueLoad whose signal is unavailable.
+type failingQueue struct{}
+
+func (failingQueue) QueueTime(context.Context, string) (time.Duration, error) {
+	return 0, errors.New("queue service unavailable")
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	estimator, err := NewEstimator(models.ETAConfig{
+		PreparationTime:       10 * time.Minute,
+		VenuePreparationTimes: map[string]time.Duration{"slow-venue": 20 * time.Minute},
+		Spread:                0.2,
+		Profiles: []models.CourierProfileConfig{
+			{Name: "walking", SpeedKMH: 6, MaxDistance: 1000},
+			{Name: "car", SpeedKMH: 30},
+		},
+	}, WithQueueLoad(StaticQueueLoad{"busy-venue": 5 * time.Minute}))
+	if err != nil {
+		t.Fatalf("NewEstimator: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		venueSlug string
+		distance  int
+		mode      string
+		want      models.DeliveryEstimate
+	}{
+		// 10 minutes of preparation and 1000 m at 6 km/h take 20 minutes.
+		{"Walking", "venue", 1000, "", models.DeliveryEstimate{Expected: 20, Min: 16, Max: 24}},
+		// 10 minutes of preparation and 5000 m at 30 km/h take 20 minutes.
+		{"Car", "venue", 5000, "", models.DeliveryEstimate{Expected: 20, Min: 16, Max: 24}},
+		// The courier mode takes precedence over the distance: 1000 m at 30 km/h take 2 minutes.
+		{"CourierMode", "venue", 1000, "car", models.DeliveryEstimate{Expected: 12, Min: 9, Max: 15}},
+		{"UnknownCourierMode", "venue", 1000, "drone", models.DeliveryEstimate{Expected: 20, Min: 16, Max: 24}},
+		{"VenuePreparationTime", "slow-venue", 0, "", models.DeliveryEstimate{Expected: 20, Min: 16, Max: 24}},
+		{"QueueLoad", "busy-venue", 0, "", models.DeliveryEstimate{Expected: 15, Min: 12, Max: 18}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, estimator.Estimate(context.Background(), tt.venueSlug, tt.distance, tt.mode))
+		})
+	}
+}
+
+func TestEstimator_FailingQueueLoad(t *testing.T) {
+	estimator, err := NewEstimator(models.ETAConfig{}, WithQueueLoad(failingQueue{}))
+	if err != nil {
+		t.Fatalf("NewEstimator: %v", err)
+	}
+
+	// The defaults apply: 10 minutes of preparation and 500 m walking at 5 km/h take 16 minutes.
+	assert.Equal(t, models.DeliveryEstimate{Expected: 16, Min: 12, Max: 20}, estimator.Estimate(context.Background(), "venue", 500, ""))
+}
+
+func TestNewEstimator_Errors(t *testing.T) {
+	_, err := NewEstimator(models.ETAConfig{Spread: 1})
+	assert.Error(t, err)
+
+	_, err = NewEstimator(models.ETAConfig{Profiles: []models.CourierProfileConfig{{Name: "bike"}}})
+	assert.ErrorContains(t, err, "bike")
+}
@@ -0,0 +1,172 @@
+package exclusions
+
+import (
+	"backend-wolt-go/internal/geo"
+	"backend-wolt-go/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// DefaultReason is the reason code of exclusions that do not set one.
+const DefaultReason = "excluded_area"
+
+// ErrInvalidExclusion is returned when an exclusion has no ID or no valid geometry.
+var ErrInvalidExclusion = errors.New("invalid exclusion")
+
+// NotServiceableError is returned when the delivery address lies in an excluded area.
+type NotServiceableError struct {
+	Reason      string // Reason code of the exclusion, e.g. "airport".
+	ExclusionID string // ID of the matching exclusion.
+}
+
+func (e *NotServiceableError) Error() string {
+	return "address not serviceable: " + e.Reason
+}
+
+// Exclusion is an area that is never delivered to, by every venue or by one venue.
+type Exclusion struct {
+	ID        string          `json:"id"`
+	VenueSlug string          `json:"venue_slug,omitempty"` // Empty for every venue.
+	Reason    string          `json:"reason"`
+	Geometry  json.RawMessage `json:"geometry"` // GeoJSON Polygon or MultiPolygon geometry.
+}
+
+// entry is an exclusion with its parsed geometry.
+type entry struct {
+	exclusion Exclusion
+	area      geo.Area
+}
+
+// Store holds the excluded areas. It is safe for concurrent use, so that exclusions can be
+// updated while orders are priced.
+type Store struct {
+	mu      sync.RWMutex
+	entries map[string]entry // Exclusions by ID.
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{entries: make(map[string]entry)}
+}
+
+// featureCollection is a GeoJSON FeatureCollection of excluded areas.
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Properties struct {
+			ID        string `json:"id"`
+			VenueSlug string `json:"venue_slug"`
+			Reason    string `json:"reason"`
+		} `json:"properties"`
+		Geometry json.RawMessage `json:"geometry"`
+	} `json:"features"`
+}
+
+// Load builds a Store from the exclusions of the GeoJSON file, if any, and those defined inline
+// in the configuration.
+func Load(config models.ExclusionsConfig) (*Store, error) {
+	s := NewStore()
+
+	if config.File != "" {
+		data, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, fmt.Errorf("could not read exclusion file: %w", err)
+		}
+		var collection featureCollection
+		if err := json.Unmarshal(data, &collection); err != nil {
+			return nil, fmt.Errorf("could not decode exclusion file: %w", err)
+		}
+		if collection.Type != "FeatureCollection" {
+			return nil, fmt.Errorf("exclusion file is a %q, want a FeatureCollection", collection.Type)
+		}
+		for _, feature := range collection.Features {
+			properties := feature.Properties
+			if err := s.Put(Exclusion{ID: properties.ID, VenueSlug: properties.VenueSlug, Reason: properties.Reason, Geometry: feature.Geometry}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, area := range config.Areas {
+		geometry, err := json.Marshal(area.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("exclusion %q: %w", area.ID, err)
+		}
+		if err := s.Put(Exclusion{ID: area.ID, VenueSlug: area.VenueSlug, Reason: area.Reason, Geometry: geometry}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Put adds the exclusion, replacing the one with the same ID if any.
+func (s *Store) Put(exclusion Exclusion) error {
+	if exclusion.ID == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidExclusion)
+	}
+	area, err := geo.ParseGeometry(exclusion.Geometry)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidExclusion, exclusion.ID, err)
+	}
+	if exclusion.Reason == "" {
+		exclusion.Reason = DefaultReason
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[exclusion.ID] = entry{exclusion: exclusion, area: area}
+	return nil
+}
+
+// Delete removes the exclusion with the ID and reports whether it existed.
+func (s *Store) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.entries[id]
+	delete(s.entries, id)
+	return ok
+}
+
+// Get returns the exclusion with the ID, if any.
+func (s *Store) Get(id string) (Exclusion, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[id]
+	return e.exclusion, ok
+}
+
+// List returns the exclusions ordered by ID.
+func (s *Store) List() []Exclusion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Exclusion, 0, len(s.entries))
+	for _, e := range s.entries {
+		list = append(list, e.exclusion)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Check returns a *NotServiceableError if the location lies in an exclusion applying to every
+// venue or to the venue. When several exclusions match, the one with the smallest ID is reported.
+func (s *Store) Check(venueSlug string, lat, lon float64) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var match *entry
+	for id, e := range s.entries {
+		if e.exclusion.VenueSlug != "" && e.exclusion.VenueSlug != venueSlug {
+			continue
+		}
+		if (match == nil || id < match.exclusion.ID) && e.area.Contains(lon, lat) {
+			match = &e
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return &NotServiceableError{Reason: match.exclusion.Reason, ExclusionID: match.exclusion.ID}
+}
@@ -0,0 +1,110 @@
+package exclusions
+
+import (
+	"backend-wolt-go/internal/models"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// square returns a GeoJSON polygon of a 1x1 degree square with its south west corner at lon, lat.
+func square(lon, lat float64) json.RawMessage {
+	data, _ := json.Marshal(map[string]any{
+		"type":        "Polygon",
+		"coordinates": [][][2]float64{{{lon, lat}, {lon + 1, lat}, {lon + 1, lat + 1}, {lon, lat + 1}, {lon, lat}}},
+	})
+	return data
+}
+
+func TestLoad(t *testing.T) {
+	store, err := Load(models.ExclusionsConfig{
+		File: "testdata/exclusions.geojson",
+		Areas: []models.ExclusionConfig{{
+			ID: "military",
+			Geometry: map[string]any{
+				"type":        "Polygon",
+				"coordinates": []any{[]any{[]any{28.0, 60.0}, []any{29.0, 60.0}, []any{29.0, 61.0}, []any{28.0, 61.0}, []any{28.0, 60.0}}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var notServiceable *NotServiceableError
+
+	// Global exclusions apply to every venue.
+	err = store.Check("other-venue", 60.5, 24.5)
+	if assert.ErrorAs(t, err, &notServiceable) {
+		assert.Equal(t, "airport", notServiceable.Reason)
+		assert.Equal(t, "airport", notServiceable.ExclusionID)
+	}
+
+	// Venue exclusions only apply to their venue.
+	err = store.Check("venue", 60.5, 26.5)
+	if assert.ErrorAs(t, err, &notServiceable) {
+		assert.Equal(t, "no_bridge", notServiceable.Reason)
+	}
+	assert.NoError(t, store.Check("other-venue", 60.5, 26.5))
+
+	// Exclusions without a reason get the default one.
+	err = store.Check("venue", 60.5, 28.5)
+	if assert.ErrorAs(t, err, &notServiceable) {
+		assert.Equal(t, DefaultReason, notServiceable.Reason)
+	}
+
+	assert.NoError(t, store.Check("venue", 60.5, 25.5))
+}
+
+func TestStore_Update(t *testing.T) {
+	store := NewStore()
+	assert.NoError(t, store.Put(Exclusion{ID: "b", Reason: "airport", Geometry: square(24, 60)}))
+	assert.NoError(t, store.Put(Exclusion{ID: "a", VenueSlug: "venue", Reason: "closed_area", Geometry: square(24, 60)}))
+
+	// The exclusion with the smallest ID is reported.
+	var notServiceable *NotServiceableError
+	if assert.ErrorAs(t, store.Check("venue", 60.5, 24.5), &notServiceable) {
+		assert.Equal(t, "a", notServiceable.ExclusionID)
+	}
+
+	// Putting an existing ID replaces the exclusion.
+	assert.NoError(t, store.Put(Exclusion{ID: "b", Reason: "airport", Geometry: square(30, 60)}))
+	assert.NoError(t, store.Check("other-venue", 60.5, 24.5))
+
+	list := store.List()
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, "a", list[0].ID)
+		assert.Equal(t, "b", list[1].ID)
+	}
+
+	assert.True(t, store.Delete("a"))
+	assert.False(t, store.Delete("a"))
+	assert.NoError(t, store.Check("venue", 60.5, 24.5))
+}
+
+func TestStore_PutInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		exclusion Exclusion
+	}{
+		{"MissingID", Exclusion{Geometry: square(24, 60)}},
+		{"MissingGeometry", Exclusion{ID: "a"}},
+		{"OpenRing", Exclusion{ID: "a", Geometry: json.RawMessage(`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewStore().Put(tt.exclusion)
+			assert.ErrorIs(t, err, ErrInvalidExclusion)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(models.ExclusionsConfig{File: "testdata/missing.geojson"})
+	assert.Error(t, err)
+
+	_, err = Load(models.ExclusionsConfig{Areas: []models.ExclusionConfig{{ID: "a", Geometry: map[string]any{"type": "Point"}}}})
+	assert.ErrorIs(t, err, ErrInvalidExclusion)
+}
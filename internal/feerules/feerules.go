@@ -0,0 +1,77 @@
+package feerules
+
+import (
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/models"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Rule is a time-dependent modifier of the delivery fee, such as a surge on weekend evenings.
+type Rule struct {
+	Name       string
+	VenueSlugs []string // Venues the rule applies to; empty for every venue.
+	Multiplier float64  // Factor applied to the delivery fee.
+	Surcharge  int      // Amount added to the delivery fee.
+
+	hours *availability.Schedule // Weekly hours during which the rule is active.
+}
+
+// appliesTo reports whether the rule is active for the venue at t.
+func (r Rule) appliesTo(venueSlug string, t time.Time) bool {
+	if len(r.VenueSlugs) > 0 && !slices.Contains(r.VenueSlugs, venueSlug) {
+		return false
+	}
+	return r.hours.IsOpen(t)
+}
+
+// Rules is an ordered list of fee rules.
+type Rules []Rule
+
+// Load parses the fee rules of the configuration.
+func Load(configs []models.FeeRuleConfig) (Rules, error) {
+	rules := make(Rules, 0, len(configs))
+	for i, config := range configs {
+		if config.Name == "" {
+			return nil, fmt.Errorf("fee rule %d has no name", i)
+		}
+		if config.Multiplier < 0 {
+			return nil, fmt.Errorf("fee rule %s: multiplier must not be negative", config.Name)
+		}
+		if config.Surcharge < 0 {
+			return nil, fmt.Errorf("fee rule %s: surcharge must not be negative", config.Name)
+		}
+		hours, err := availability.ParseSchedule(config.Timezone, config.Hours)
+		if err != nil {
+			return nil, fmt.Errorf("fee rule %s: %w", config.Name, err)
+		}
+		multiplier := config.Multiplier
+		if multiplier == 0 {
+			multiplier = 1
+		}
+		rules = append(rules, Rule{
+			Name:       config.Name,
+			VenueSlugs: config.VenueSlugs,
+			Multiplier: multiplier,
+			Surcharge:  config.Surcharge,
+			hours:      hours,
+		})
+	}
+	return rules, nil
+}
+
+// Apply applies the rules active for the venue at t to the delivery fee, in order, and returns
+// the resulting fee and the names of the applied rules.
+func (r Rules) Apply(venueSlug string, t time.Time, fee int) (int, []string) {
+	var applied []string
+	for _, rule := range r {
+		if !rule.appliesTo(venueSlug, t) {
+			continue
+		}
+		fee = int(math.Round(float64(fee)*rule.Multiplier)) + rule.Surcharge
+		applied = append(applied, rule.Name)
+	}
+	return fee, applied
+}
@@ -0,0 +1,75 @@
+package feerules
+
+import (
+	"backend-wolt-go/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Apply(t *testing.T) {
+	rules, err := Load([]models.FeeRuleConfig{
+		{
+			Name:       "evening-surge",
+			Timezone:   "Europe/Helsinki",
+			Hours:      map[string][]string{"friday": {"17:00-21:00"}},
+			Multiplier: 1.5,
+		},
+		{
+			Name:       "late-night",
+			VenueSlugs: []string{"night-venue"},
+			Timezone:   "Europe/Helsinki",
+			Hours:      map[string][]string{"friday": {"20:00-04:00"}},
+			Surcharge:  100,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		venueSlug string
+		at        time.Time
+		wantFee   int
+		wantRules []string
+	}{
+		{"NoRule", "venue", time.Date(2030, time.January, 4, 12, 0, 0, 0, helsinki), 300, nil},
+		{"Surge", "venue", time.Date(2030, time.January, 4, 18, 0, 0, 0, helsinki), 450, []string{"evening-surge"}},
+		{"OtherVenue", "venue", time.Date(2030, time.January, 4, 20, 30, 0, 0, helsinki), 450, []string{"evening-surge"}},
+		{"AppliedInOrder", "night-venue", time.Date(2030, time.January, 4, 20, 30, 0, 0, helsinki), 550, []string{"evening-surge", "late-night"}},
+		{"AfterMidnight", "night-venue", time.Date(2030, time.January, 5, 1, 0, 0, 0, helsinki), 400, []string{"late-night"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, applied := rules.Apply(tt.venueSlug, tt.at, 300)
+			assert.Equal(t, tt.wantFee, fee)
+			assert.Equal(t, tt.wantRules, applied)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.FeeRuleConfig
+	}{
+		{"MissingName", models.FeeRuleConfig{Hours: map[string][]string{"monday": {"10:00-12:00"}}}},
+		{"NegativeMultiplier", models.FeeRuleConfig{Name: "rule", Multiplier: -1}},
+		{"NegativeSurcharge", models.FeeRuleConfig{Name: "rule", Surcharge: -100}},
+		{"InvalidHours", models.FeeRuleConfig{Name: "rule", Hours: map[string][]string{"monday": {"noon"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]models.FeeRuleConfig{tt.config})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Point is a position in the GeoJSON order: [longitude, latitude].
+type Point [2]float64
+
+// Ring is a closed line: its first and last points are equal.
+type Ring []Point
+
+// Polygon is an exterior ring followed by the rings of its holes.
+type Polygon []Ring
+
+// Area is a set of polygons, built from a GeoJSON Polygon or MultiPolygon geometry.
+type Area []Polygon
+
+// geometry is a GeoJSON geometry object.
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ParseGeometry parses a GeoJSON Polygon or MultiPolygon geometry into an Area.
+func ParseGeometry(data []byte) (Area, error) {
+	var g geometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON geometry: %w", err)
+	}
+
+	var area Area
+	switch g.Type {
+	case "Polygon":
+		var polygon Polygon
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		area = Area{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &area); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q, want Polygon or MultiPolygon", g.Type)
+	}
+
+	if err := area.validate(); err != nil {
+		return nil, err
+	}
+	return area, nil
+}
+
+// validate checks that every polygon has an exterior ring and that every ring is closed,
+// has at least four points and only holds valid coordinates.
+func (a Area) validate() error {
+	if len(a) == 0 {
+		return errors.New("geometry has no polygon")
+	}
+	for _, polygon := range a {
+		if len(polygon) == 0 {
+			return errors.New("polygon has no exterior ring")
+		}
+		for _, ring := range polygon {
+			if len(ring) < 4 {
+				return fmt.Errorf("ring has %d points, want at least 4", len(ring))
+			}
+			if ring[0] != ring[len(ring)-1] {
+				return errors.New("ring is not closed")
+			}
+			for _, p := range ring {
+				if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+					return fmt.Errorf("position %v out of range", p)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Contains reports whether the point lies inside one of the polygons of the area and outside
+// of its holes. Edges are treated as straight lines in longitude and latitude, which is accurate
+// for areas the size of a city.
+func (a Area) Contains(lon, lat float64) bool {
+	for _, polygon := range a {
+		if polygon.contains(lon, lat) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether the point lies inside the exterior ring and outside every hole.
+func (p Polygon) contains(lon, lat float64) bool {
+	if !p[0].contains(lon, lat) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(lon, lat) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether the point lies inside the ring, using ray casting: a ray going east
+// from the point crosses the ring an odd number of times if and only if the point is inside.
+func (r Ring) contains(lon, lat float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// square is a 1x1 degree square with a 0.5x0.5 hole in its middle.
+const square = `{"type": "Polygon", "coordinates": [
+	[[24, 60], [25, 60], [25, 61], [24, 61], [24, 60]],
+	[[24.25, 60.25], [24.75, 60.25], [24.75, 60.75], [24.25, 60.75], [24.25, 60.25]]
+]}`
+
+func TestArea_Contains(t *testing.T) {
+	area, err := ParseGeometry([]byte(square))
+	assert.NoError(t, err)
+
+	assert.True(t, area.Contains(24.1, 60.1))
+	assert.True(t, area.Contains(24.9, 60.9))
+	assert.False(t, area.Contains(24.5, 60.5), "inside the hole")
+	assert.False(t, area.Contains(25.1, 60.5), "east of the square")
+	assert.False(t, area.Contains(24.5, 59.9), "south of the square")
+}
+
+func TestArea_ContainsConcave(t *testing.T) {
+	// An L-shaped polygon: the top right quarter is outside.
+	area, err := ParseGeometry([]byte(`{"type": "Polygon", "coordinates": [
+		[[0, 0], [2, 0], [2, 1], [1, 1], [1, 2], [0, 2], [0, 0]]
+	]}`))
+	assert.NoError(t, err)
+
+	assert.True(t, area.Contains(0.5, 1.5))
+	assert.True(t, area.Contains(1.5, 0.5))
+	assert.False(t, area.Contains(1.5, 1.5))
+}
+
+func TestArea_ContainsMultiPolygon(t *testing.T) {
+	area, err := ParseGeometry([]byte(`{"type": "MultiPolygon", "coordinates": [
+		[[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]],
+		[[[2, 0], [3, 0], [3, 1], [2, 1], [2, 0]]]
+	]}`))
+	assert.NoError(t, err)
+
+	assert.True(t, area.Contains(0.5, 0.5))
+	assert.True(t, area.Contains(2.5, 0.5))
+	assert.False(t, area.Contains(1.5, 0.5))
+}
+
+func TestParseGeometry_Errors(t *testing.T) {
+	tests := map[string]string{
+		"malformed":   `{"type": "Polygon", "coordinates": [[[0, 0]`,
+		"type":        `{"type": "Point", "coordinates": [0, 0]}`,
+		"empty":       `{"type": "MultiPolygon", "coordinates": []}`,
+		"short ring":  `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0]]]}`,
+		"open ring":   `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`,
+		"coordinates": `{"type": "Polygon", "coordinates": [[[0, 0], [200, 0], [1, 1], [0, 0]]]}`,
+	}
+	for name, geometry := range tests {
+		_, err := ParseGeometry([]byte(geometry))
+		assert.Error(t, err, name)
+	}
+}
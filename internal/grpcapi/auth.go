@@ -0,0 +1,126 @@
+package grpcapi
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/logging"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationMetadata is the metadata key carrying the bearer token, like the Authorization
+// header of the REST endpoint.
+const authorizationMetadata = "authorization"
+
+// Authenticator authenticates calls with the credentials found in their metadata, like the REST
+// middlewares: a bearer token in the authorization metadata when bearer tokens are enabled,
+// falling back to an API key in the metadata named after the API key header.
+type Authenticator struct {
+	keys        auth.KeyStore      // Looks up API keys, if enabled.
+	keyMetadata string             // Metadata key carrying the API key.
+	validator   *auth.JWTValidator // Validates bearer tokens, if enabled.
+}
+
+// NewAuthenticator creates an Authenticator looking up the API keys sent in the keyHeader
+// metadata in keys and validating bearer tokens with validator. Either may be nil to disable it.
+func NewAuthenticator(keys auth.KeyStore, keyHeader string, validator *auth.JWTValidator) *Authenticator {
+	return &Authenticator{keys: keys, keyMetadata: strings.ToLower(keyHeader), validator: validator}
+}
+
+// UnaryAuthInterceptor rejects calls of the DeliveryPricing service without valid credentials
+// with Unauthenticated, and those whose identity lacks the pricing:read scope with
+// PermissionDenied. The identity is attached to the call context. Health checks and reflection
+// are not authenticated.
+func UnaryAuthInterceptor(a *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !pricingMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is the streaming counterpart of UnaryAuthInterceptor. The credentials
+// are checked once, when the stream is opened.
+func StreamAuthInterceptor(a *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !pricingMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns a copy of ctx carrying the identity of the credentials of the call, or a
+// status error if they are missing, invalid or lack the pricing:read scope.
+func (a *Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	identity, err := a.identity(ctx, md)
+	if err != nil {
+		return nil, err
+	}
+	if !identity.HasScope(auth.ScopePricingRead) {
+		return nil, status.Error(codes.PermissionDenied, "Insufficient scope")
+	}
+
+	if identity.KeyID != "" {
+		ctx = logging.WithAttrs(ctx, slog.String("key_id", identity.KeyID), slog.String("tenant", identity.Tenant))
+	} else {
+		ctx = logging.WithAttrs(ctx, slog.String("subject", identity.Subject), slog.String("tenant", identity.Tenant))
+	}
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+// identity validates the bearer token of the metadata, or looks up its API key when it has no
+// token, with the messages of the REST middlewares.
+func (a *Authenticator) identity(ctx context.Context, md metadata.MD) (*auth.Identity, error) {
+	if a.validator != nil {
+		if token, ok := strings.CutPrefix(first(md, authorizationMetadata), "Bearer "); ok && token != "" {
+			identity, err := a.validator.Validate(ctx, token)
+			if err != nil {
+				logging.FromContext(ctx).InfoContext(ctx, "bearer token rejected", slog.String("error", err.Error()))
+				return nil, status.Error(codes.Unauthenticated, "Invalid bearer token")
+			}
+			return identity, nil
+		}
+		if a.keys == nil {
+			return nil, status.Error(codes.Unauthenticated, "Missing bearer token")
+		}
+	}
+
+	apiKey := first(md, a.keyMetadata)
+	if apiKey == "" {
+		return nil, status.Error(codes.Unauthenticated, "Missing API key")
+	}
+	identity, err := a.keys.Lookup(ctx, apiKey)
+	if err != nil {
+		if !errors.Is(err, auth.ErrUnknownKey) {
+			logging.FromContext(ctx).ErrorContext(ctx, "API key lookup failed", slog.String("error", err.Error()))
+			return nil, status.Error(codes.Unavailable, "Authentication unavailable")
+		}
+		return nil, status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+	return identity, nil
+}
+
+// first returns the first value of the metadata key, or an empty string.
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
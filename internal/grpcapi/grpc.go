@@ -0,0 +1,269 @@
+package grpcapi
+
+import (
+	"backend-wolt-go/internal/api"
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/grpcapi/dopcv1"
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/ratelimit"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// requestIDMetadata is the metadata key carrying the request ID, like the X-Request-Id header
+// of the REST endpoint.
+const requestIDMetadata = "x-request-id"
+
+// Option configures optional interceptors of the gRPC server.
+type Option func(*serverOptions)
+
+// serverOptions holds the optional dependencies of the gRPC server.
+type serverOptions struct {
+	authenticator *Authenticator
+	limiter       *ratelimit.Limiter
+}
+
+// WithAuthenticator authenticates the calls of the DeliveryPricing service with authenticator.
+func WithAuthenticator(authenticator *Authenticator) Option {
+	return func(o *serverOptions) {
+		o.authenticator = authenticator
+	}
+}
+
+// WithLimiter rate limits calls per authenticated client, or per client IP without
+// authentication, with limiter. Every message of a batch stream takes a token, like a REST
+// request does.
+func WithLimiter(limiter *ratelimit.Limiter) Option {
+	return func(o *serverOptions) {
+		o.limiter = limiter
+	}
+}
+
+// NewGRPCServer creates a gRPC server serving the DeliveryPricing service backed by service,
+// the standard health checking service and server reflection. The health server reports the
+// whole server and the DeliveryPricing service as serving until it is shut down.
+func NewGRPCServer(service api.DOPCService, logger *slog.Logger, opts ...Option) (*grpc.Server, *health.Server) {
+	var o serverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	unary := []grpc.UnaryServerInterceptor{UnaryLoggingInterceptor(logger), UnaryRecoveryInterceptor}
+	stream := []grpc.StreamServerInterceptor{StreamLoggingInterceptor(logger), StreamRecoveryInterceptor}
+	if o.authenticator != nil {
+		unary = append(unary, UnaryAuthInterceptor(o.authenticator))
+		stream = append(stream, StreamAuthInterceptor(o.authenticator))
+	}
+	if o.limiter != nil {
+		unary = append(unary, UnaryRateLimitInterceptor(o.limiter))
+		stream = append(stream, StreamRateLimitInterceptor(o.limiter))
+	}
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	dopcv1.RegisterDeliveryPricingServer(srv, NewServer(service))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(dopcv1.DeliveryPricing_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+
+	reflection.Register(srv)
+	return srv, healthServer
+}
+
+// UnaryLoggingInterceptor attaches a logger carrying the request ID to the call context and
+// logs every completed call, like logging.Middleware does for HTTP requests.
+func UnaryLoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = withRequestLogger(ctx, logger)
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamLoggingInterceptor is the streaming counterpart of UnaryLoggingInterceptor. The call is
+// logged once the stream ends.
+func StreamLoggingInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withRequestLogger(ss.Context(), logger)
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryRecoveryInterceptor turns a panic of the handler into an Internal error, like
+// middleware.Recoverer does for HTTP requests, so that it does not crash the server.
+func UnaryRecoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (_ any, err error) {
+	defer recoverPanic(ctx, &err)
+	return handler(ctx, req)
+}
+
+// StreamRecoveryInterceptor is the streaming counterpart of UnaryRecoveryInterceptor.
+func StreamRecoveryInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverPanic(ss.Context(), &err)
+	return handler(srv, ss)
+}
+
+// recoverPanic recovers a panic of the handler, logs it with the stack trace and replaces the
+// error of the call with an Internal error that does not leak the panic value.
+func recoverPanic(ctx context.Context, err *error) {
+	if r := recover(); r != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "panic recovered",
+			slog.Any("panic", r),
+			slog.String("stack", string(debug.Stack())),
+		)
+		*err = status.Error(codes.Internal, "Internal server error")
+	}
+}
+
+// UnaryRateLimitInterceptor rejects calls of the DeliveryPricing service with ResourceExhausted
+// once the bucket of the client is empty. Health checks and reflection are not limited. If the
+// store fails the call is let through, like ratelimit.Middleware does.
+func UnaryRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !pricingMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if err := allow(ctx, limiter); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor is the streaming counterpart of UnaryRateLimitInterceptor. Every
+// received message takes a token, and the stream ends with ResourceExhausted once the bucket is
+// empty.
+func StreamRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !pricingMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return handler(srv, &limitedStream{ServerStream: ss, limiter: limiter})
+	}
+}
+
+// pricingMethod reports whether the method belongs to the DeliveryPricing service.
+func pricingMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+dopcv1.DeliveryPricing_ServiceDesc.ServiceName+"/")
+}
+
+// limitedStream is a server stream taking a token for every received message.
+type limitedStream struct {
+	grpc.ServerStream
+	limiter *ratelimit.Limiter
+}
+
+// RecvMsg receives the next message if the client's bucket is not empty.
+func (s *limitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return allow(s.Context(), s.limiter)
+}
+
+// allow takes a token from the bucket of the client. It returns a ResourceExhausted status
+// carrying the retry delay when the bucket is empty, and nil when the store fails.
+func allow(ctx context.Context, limiter *ratelimit.Limiter) error {
+	_, err := limiter.Allow(ctx, clientKey(ctx))
+	var limitErr *ratelimit.LimitExceededError
+	if errors.As(err, &limitErr) {
+		st := status.New(codes.ResourceExhausted, "Rate limit exceeded")
+		if withDetail, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(limitErr.RetryAfter)}); detailErr == nil {
+			st = withDetail
+		}
+		return st.Err()
+	}
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "rate limit store failed", slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// clientKey returns the bucket key of the authenticated identity of the call, or of its peer, in
+// the format of ratelimit.IdentityOrIP.
+func clientKey(ctx context.Context) string {
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		switch {
+		case identity.KeyID != "":
+			return "key:" + identity.KeyID
+		case identity.Subject != "":
+			return "sub:" + identity.Subject
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+// withRequestLogger returns a copy of ctx carrying the request ID, taken from the metadata of
+// the call or generated, and a logger carrying it. The request ID is stored under the key of
+// chi's middleware.RequestID, so that it is recorded in the quote log as for HTTP requests.
+func withRequestLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadata); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = fmt.Sprintf("grpc-%06d", middleware.NextRequestID())
+	}
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, requestID)
+	return logging.WithLogger(ctx, logger.With(slog.String("request_id", requestID)))
+}
+
+// logCall logs a completed call with its status code, at error level for server errors.
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	}
+	logging.FromContext(ctx).LogAttrs(ctx, level, "call completed",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+	)
+}
+
+// contextStream is a server stream whose context is replaced.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,239 @@
+// Package grpcapi serves the delivery order price calculation over gRPC, alongside the REST
+// endpoint and backed by the same DOPC service.
+package grpcapi
+
+import (
+	"backend-wolt-go/internal/api"
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/client"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/grpcapi/dopcv1"
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
+	"backend-wolt-go/internal/service"
+	"backend-wolt-go/internal/utils"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details attached to the errors clients can act upon.
+const ErrorDomain = "dopc.v1"
+
+// tracer creates the spans for the gRPC methods.
+var tracer = otel.Tracer("backend-wolt-go/internal/grpcapi")
+
+// Server implements the DeliveryPricing service on top of the DOPC service.
+type Server struct {
+	dopcv1.UnimplementedDeliveryPricingServer
+
+	service api.DOPCService
+	now     func() time.Time
+}
+
+// NewServer creates a new Server instance with the provided DOPCService.
+func NewServer(service api.DOPCService) *Server {
+	return &Server{service: service, now: time.Now}
+}
+
+// GetDeliveryOrderPrice validates the order, calls the service and returns the price.
+func (s *Server) GetDeliveryOrderPrice(ctx context.Context, req *dopcv1.GetDeliveryOrderPriceRequest) (*dopcv1.GetDeliveryOrderPriceResponse, error) {
+	ctx, span := tracer.Start(ctx, "Server.GetDeliveryOrderPrice")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("venue.slug", req.GetVenueSlug()), attribute.Int64("order.cart_value", req.GetCartValue()))
+
+	response, err := s.price(ctx, req)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+// BatchGetDeliveryOrderPrices prices the orders of the stream one by one, replying to each with
+// the ID of its request. A failed order is reported in its reply with the status the unary
+// method would have returned, and the stream goes on.
+func (s *Server) BatchGetDeliveryOrderPrices(stream dopcv1.DeliveryPricing_BatchGetDeliveryOrderPricesServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		reply := &dopcv1.BatchGetDeliveryOrderPricesResponse{Id: req.GetId()}
+		price, err := s.GetDeliveryOrderPrice(stream.Context(), req.GetOrder())
+		if err != nil {
+			reply.Result = &dopcv1.BatchGetDeliveryOrderPricesResponse_Error{Error: status.Convert(err).Proto()}
+		} else {
+			reply.Result = &dopcv1.BatchGetDeliveryOrderPricesResponse_Price{Price: price}
+		}
+		if err := stream.Send(reply); err != nil {
+			return err
+		}
+	}
+}
+
+// price validates the order and prices it, returning errors as gRPC statuses. Orders of venues
+// the authenticated identity may not price are rejected with PermissionDenied.
+func (s *Server) price(ctx context.Context, req *dopcv1.GetDeliveryOrderPriceRequest) (*dopcv1.GetDeliveryOrderPriceResponse, error) {
+	orderInfo, err := s.orderInfo(req)
+	if err != nil {
+		return nil, err
+	}
+	if identity, ok := auth.IdentityFromContext(ctx); ok && !identity.AllowsVenue(orderInfo.Slug) {
+		return nil, status.Error(codes.PermissionDenied, "Venue not allowed for this API key")
+	}
+	response, err := s.service.CalculateDeliveryFee(ctx, orderInfo)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+	return toProto(response), nil
+}
+
+// orderInfo validates the request like the query parameters of the REST endpoint, with the same
+// messages.
+func (s *Server) orderInfo(req *dopcv1.GetDeliveryOrderPriceRequest) (*models.OrderInfo, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "Missing required parameter: order")
+	}
+	if req.GetVenueSlug() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing required parameter: venue_slug")
+	}
+	lat, lon := req.GetUserLat(), req.GetUserLon()
+	if math.IsNaN(lat) || math.IsInf(lat, 0) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid user latitude")
+	}
+	if lat < -90 || lat > 90 {
+		return nil, status.Error(codes.InvalidArgument, "Latitude must be between -90 and 90")
+	}
+	if math.IsNaN(lon) || math.IsInf(lon, 0) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid user longitude")
+	}
+	if lon < -180 || lon > 180 {
+		return nil, status.Error(codes.InvalidArgument, "Longitude must be between -180 and 180")
+	}
+	if req.GetCartValue() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Cart value must be a positive integer")
+	}
+	if req.GetCartValue() > math.MaxInt32 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid cart value")
+	}
+
+	orderInfo := &models.OrderInfo{
+		Slug:        req.GetVenueSlug(),
+		Lat:         lat,
+		Lon:         lon,
+		CartValue:   int(req.GetCartValue()),
+		CourierMode: req.GetCourierMode(),
+	}
+	if req.ScheduledFor != nil {
+		if err := req.GetScheduledFor().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid delivery time")
+		}
+		deliveryTime := req.GetScheduledFor().AsTime()
+		if deliveryTime.Before(s.now()) {
+			return nil, status.Error(codes.InvalidArgument, "Delivery time must not be in the past")
+		}
+		orderInfo.DeliveryTime = deliveryTime
+	}
+	return orderInfo, nil
+}
+
+// serviceError converts an error of the pricing service to a status whose code matches the
+// status code of the REST endpoint: ResourceExhausted for 429 and FailedPrecondition for 422,
+// with the retry delay or the reason code in the details, and NotFound, Unavailable and
+// DeadlineExceeded for 404, 502 and 504. Unexpected errors do not leak their message.
+func serviceError(ctx context.Context, err error) error {
+	var limitErr *ratelimit.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return withDetails(codes.ResourceExhausted, err, &errdetails.RetryInfo{RetryDelay: durationpb.New(limitErr.RetryAfter)})
+	}
+	var notServiceableErr *exclusions.NotServiceableError
+	if errors.As(err, &notServiceableErr) {
+		return withDetails(codes.FailedPrecondition, err, &errdetails.ErrorInfo{Reason: notServiceableErr.Reason, Domain: ErrorDomain})
+	}
+	var modeErr *couriers.ModeUnavailableError
+	if errors.As(err, &modeErr) {
+		return withDetails(codes.FailedPrecondition, err, &errdetails.ErrorInfo{Reason: couriers.ReasonModeUnavailable, Domain: ErrorDomain})
+	}
+	var notDeliveringErr *availability.NotDeliveringError
+	if errors.As(err, &notDeliveringErr) {
+		info := &errdetails.ErrorInfo{Reason: notDeliveringErr.Reason, Domain: ErrorDomain}
+		if !notDeliveringErr.NextAvailableAt.IsZero() {
+			info.Metadata = map[string]string{"next_available_at": notDeliveringErr.NextAvailableAt.Format(time.RFC3339)}
+		}
+		return withDetails(codes.FailedPrecondition, err, info)
+	}
+	if errors.Is(err, utils.ErrDeliveryNotPossible) {
+		return withDetails(codes.FailedPrecondition, utils.ErrDeliveryNotPossible, &errdetails.ErrorInfo{Reason: utils.ReasonDistanceTooLong, Domain: ErrorDomain})
+	}
+
+	logging.FromContext(ctx).ErrorContext(ctx, "failed to price order", slog.String("error", err.Error()))
+	switch {
+	case errors.Is(err, service.ErrUpstreamTimeout):
+		return status.Error(codes.DeadlineExceeded, "Venue API timed out")
+	case errors.Is(err, service.ErrUpstream), errors.Is(err, client.ErrInvalidVenueLocation):
+		return status.Error(codes.Unavailable, "Venue API unavailable")
+	case errors.Is(err, service.ErrVenueNotFound):
+		return status.Error(codes.NotFound, "Venue not found")
+	}
+	return status.Error(codes.Internal, "Internal server error")
+}
+
+// withDetails returns a status with the code, the message of err and the detail.
+func withDetails(code codes.Code, err error, detail protoadapt.MessageV1) error {
+	st := status.New(code, err.Error())
+	if withDetail, detailErr := st.WithDetails(detail); detailErr == nil {
+		st = withDetail
+	}
+	return st.Err()
+}
+
+// toProto converts the price of the service to its protobuf message.
+func toProto(response models.PriceResponse) *dopcv1.GetDeliveryOrderPriceResponse {
+	message := &dopcv1.GetDeliveryOrderPriceResponse{
+		TotalPrice:          int64(response.TotalPrice),
+		SmallOrderSurcharge: int64(response.SmallOrderSurcharge),
+		CartValue:           int64(response.CartValue),
+		Delivery: &dopcv1.Delivery{
+			Fee:      int64(response.Delivery.Fee),
+			Distance: int64(response.Delivery.Distance),
+			Zone:     response.Delivery.Zone,
+			Rules:    response.Delivery.Rules,
+			Mode:     response.Delivery.Mode,
+		},
+		Stale:   response.Stale,
+		QuoteId: response.QuoteID,
+	}
+	if response.ScheduledFor != nil {
+		message.ScheduledFor = timestamppb.New(*response.ScheduledFor)
+	}
+	if estimate := response.EstimatedDeliveryMinutes; estimate != nil {
+		message.EstimatedDeliveryMinutes = &dopcv1.DeliveryEstimate{
+			Expected: int32(estimate.Expected),
+			Min:      int32(estimate.Min),
+			Max:      int32(estimate.Max),
+		}
+	}
+	return message
+}
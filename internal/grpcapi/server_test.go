@@ -0,0 +1,429 @@
+package grpcapi
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/availability"
+	"backend-wolt-go/internal/couriers"
+	"backend-wolt-go/internal/exclusions"
+	"backend-wolt-go/internal/grpcapi/dopcv1"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
+	"backend-wolt-go/internal/service"
+	"backend-wolt-go/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mockDOPCService struct {
+	mock.Mock
+}
+
+func (m *mockDOPCService) CalculateDeliveryFee(ctx context.Context, orderInfo *models.OrderInfo) (models.PriceResponse, error) {
+	args := m.Called(ctx, orderInfo)
+	resp, _ := args.Get(0).(models.PriceResponse)
+	return resp, args.Error(1)
+}
+
+// startServer serves the gRPC API backed by service over an in-memory connection and returns a
+// client connection to it, with the health server.
+func startServer(t *testing.T, service *mockDOPCService, opts ...Option) (*grpc.ClientConn, *health.Server) {
+	lis := bufconn.Listen(1 << 20)
+	srv, healthServer := NewGRPCServer(service, slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, healthServer
+}
+
+func validRequest() *dopcv1.GetDeliveryOrderPriceRequest {
+	return &dopcv1.GetDeliveryOrderPriceRequest{VenueSlug: "home-assignment-venue-helsinki", CartValue: 1000, UserLat: 60.17094, UserLon: 24.93087}
+}
+
+func TestGetDeliveryOrderPrice(t *testing.T) {
+	service := new(mockDOPCService)
+	conn, _ := startServer(t, service)
+	client := dopcv1.NewDeliveryPricingClient(conn)
+
+	scheduledFor := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	response := models.PriceResponse{TotalPrice: 1190, SmallOrderSurcharge: 0, CartValue: 1000, ScheduledFor: &scheduledFor, QuoteID: "quote-1"}
+	response.Delivery.Fee = 190
+	response.Delivery.Distance = 177
+	response.Delivery.Rules = []string{"rush_hour"}
+	response.EstimatedDeliveryMinutes = &models.DeliveryEstimate{Expected: 25, Min: 20, Max: 35}
+
+	var requestID string
+	service.On("CalculateDeliveryFee", mock.Anything, &models.OrderInfo{
+		Slug:         "home-assignment-venue-helsinki",
+		Lat:          60.17094,
+		Lon:          24.93087,
+		CartValue:    1000,
+		DeliveryTime: scheduledFor,
+		CourierMode:  "bike",
+	}).Run(func(args mock.Arguments) {
+		requestID = middleware.GetReqID(args.Get(0).(context.Context))
+	}).Return(response, nil).Once()
+
+	req := validRequest()
+	req.ScheduledFor = timestamppb.New(scheduledFor)
+	req.CourierMode = "bike"
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "request-1")
+	got, err := client.GetDeliveryOrderPrice(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1190), got.GetTotalPrice())
+	assert.Equal(t, int64(1000), got.GetCartValue())
+	assert.Equal(t, int64(190), got.GetDelivery().GetFee())
+	assert.Equal(t, int64(177), got.GetDelivery().GetDistance())
+	assert.Equal(t, []string{"rush_hour"}, got.GetDelivery().GetRules())
+	assert.Equal(t, scheduledFor, got.GetScheduledFor().AsTime())
+	assert.Equal(t, int32(25), got.GetEstimatedDeliveryMinutes().GetExpected())
+	assert.Equal(t, "quote-1", got.GetQuoteId())
+	assert.Equal(t, "request-1", requestID)
+	service.AssertExpectations(t)
+}
+
+func TestGetDeliveryOrderPrice_InvalidArgument(t *testing.T) {
+	service := new(mockDOPCService)
+	conn, _ := startServer(t, service)
+	client := dopcv1.NewDeliveryPricingClient(conn)
+
+	tests := []struct {
+		name    string
+		modify  func(req *dopcv1.GetDeliveryOrderPriceRequest)
+		message string
+	}{
+		{"Missing venue_slug", func(req *dopcv1.GetDeliveryOrderPriceRequest) { req.VenueSlug = "" }, "Missing required parameter: venue_slug"},
+		{"NaN latitude", func(req *dopcv1.GetDeliveryOrderPriceRequest) { req.UserLat = math.NaN() }, "Invalid user latitude"},
+		{"Latitude out of range", func(req *dopcv1.GetDeliveryOrderPriceRequest) { req.UserLat = 91 }, "Latitude must be between -90 and 90"},
+		{"Longitude out of range", func(req *dopcv1.GetDeliveryOrderPriceRequest) { req.UserLon = -181 }, "Longitude must be between -180 and 180"},
+		{"Missing cart_value", func(req *dopcv1.GetDeliveryOrderPriceRequest) { req.CartValue = 0 }, "Cart value must be a positive integer"},
+		{"Cart value too large", func(req *dopcv1.GetDeliveryOrderPriceRequest) { req.CartValue = math.MaxInt64 }, "Invalid cart value"},
+		{"Past delivery time", func(req *dopcv1.GetDeliveryOrderPriceRequest) {
+			req.ScheduledFor = timestamppb.New(time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC))
+		}, "Delivery time must not be in the past"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(req)
+			_, err := client.GetDeliveryOrderPrice(context.Background(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Equal(t, tt.message, status.Convert(err).Message())
+		})
+	}
+	service.AssertNotCalled(t, "CalculateDeliveryFee", mock.Anything, mock.Anything)
+}
+
+func TestGetDeliveryOrderPrice_ServiceErrors(t *testing.T) {
+	nextAvailableAt := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		err        error
+		code       codes.Code
+		reason     string
+		metadata   map[string]string
+		retryDelay time.Duration
+		message    string
+	}{
+		{name: "Rate limited", err: &ratelimit.LimitExceededError{Key: "venue:abc", RetryAfter: 2 * time.Second}, code: codes.ResourceExhausted, retryDelay: 2 * time.Second},
+		{name: "Not serviceable", err: &exclusions.NotServiceableError{Reason: "airport", ExclusionID: "hel"}, code: codes.FailedPrecondition, reason: "airport"},
+		{name: "Courier mode unavailable", err: &couriers.ModeUnavailableError{Mode: "walking"}, code: codes.FailedPrecondition, reason: couriers.ReasonModeUnavailable},
+		{
+			name:     "Venue closed",
+			err:      &availability.NotDeliveringError{Reason: availability.ReasonClosed, NextAvailableAt: nextAvailableAt},
+			code:     codes.FailedPrecondition,
+			reason:   availability.ReasonClosed,
+			metadata: map[string]string{"next_available_at": "2030-01-07T10:00:00Z"},
+		},
+		{name: "Delivery not possible", err: fmt.Errorf("pricing: %w", utils.ErrDeliveryNotPossible), code: codes.FailedPrecondition, reason: utils.ReasonDistanceTooLong, message: utils.ErrDeliveryNotPossible.Error()},
+		{name: "Unknown venue", err: fmt.Errorf("failed to get static data: %w", service.ErrVenueNotFound), code: codes.NotFound, message: "Venue not found"},
+		{name: "Upstream failure", err: fmt.Errorf("failed to get static data: %w", service.ErrUpstream), code: codes.Unavailable, message: "Venue API unavailable"},
+		{name: "Upstream timeout", err: fmt.Errorf("failed to get dynamic data: %w", service.ErrUpstreamTimeout), code: codes.DeadlineExceeded, message: "Venue API timed out"},
+		{name: "Other error", err: errors.New("open /var/lib/dopc: permission denied"), code: codes.Internal, message: "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockDOPCService)
+			conn, _ := startServer(t, service)
+			client := dopcv1.NewDeliveryPricingClient(conn)
+			service.On("CalculateDeliveryFee", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			_, err := client.GetDeliveryOrderPrice(context.Background(), validRequest())
+			st := status.Convert(err)
+			assert.Equal(t, tt.code, st.Code())
+			if tt.message == "" {
+				tt.message = tt.err.Error()
+			}
+			assert.Equal(t, tt.message, st.Message())
+			for _, detail := range st.Details() {
+				switch detail := detail.(type) {
+				case *errdetails.ErrorInfo:
+					assert.Equal(t, tt.reason, detail.GetReason())
+					assert.Equal(t, ErrorDomain, detail.GetDomain())
+					assert.Equal(t, tt.metadata, detail.GetMetadata())
+				case *errdetails.RetryInfo:
+					assert.Equal(t, tt.retryDelay, detail.GetRetryDelay().AsDuration())
+				default:
+					t.Errorf("unexpected detail %T", detail)
+				}
+			}
+			if tt.reason == "" && tt.retryDelay == 0 {
+				assert.Empty(t, st.Details())
+			} else {
+				assert.Len(t, st.Details(), 1)
+			}
+		})
+	}
+}
+
+func TestBatchGetDeliveryOrderPrices(t *testing.T) {
+	service := new(mockDOPCService)
+	conn, _ := startServer(t, service)
+	client := dopcv1.NewDeliveryPricingClient(conn)
+
+	service.On("CalculateDeliveryFee", mock.Anything, mock.MatchedBy(func(orderInfo *models.OrderInfo) bool {
+		return orderInfo.CartValue == 1000
+	})).Return(models.PriceResponse{TotalPrice: 1190, CartValue: 1000}, nil)
+	service.On("CalculateDeliveryFee", mock.Anything, mock.MatchedBy(func(orderInfo *models.OrderInfo) bool {
+		return orderInfo.CartValue == 500
+	})).Return(nil, &exclusions.NotServiceableError{Reason: "airport"})
+
+	stream, err := client.BatchGetDeliveryOrderPrices(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	invalid := validRequest()
+	invalid.VenueSlug = ""
+	excluded := validRequest()
+	excluded.CartValue = 500
+	requests := []*dopcv1.BatchGetDeliveryOrderPricesRequest{
+		{Id: "a", Order: validRequest()},
+		{Id: "b", Order: invalid},
+		{Id: "c", Order: excluded},
+		{Id: "d"},
+	}
+	for _, req := range requests {
+		assert.NoError(t, stream.Send(req))
+	}
+	assert.NoError(t, stream.CloseSend())
+
+	var replies []*dopcv1.BatchGetDeliveryOrderPricesResponse
+	for {
+		reply, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		replies = append(replies, reply)
+	}
+
+	// Every request gets a reply in order, and failed orders do not end the stream.
+	if assert.Len(t, replies, 4) {
+		assert.Equal(t, "a", replies[0].GetId())
+		assert.Equal(t, int64(1190), replies[0].GetPrice().GetTotalPrice())
+		assert.Equal(t, "b", replies[1].GetId())
+		assert.Equal(t, int32(codes.InvalidArgument), replies[1].GetError().GetCode())
+		assert.Equal(t, "Missing required parameter: venue_slug", replies[1].GetError().GetMessage())
+		assert.Equal(t, "c", replies[2].GetId())
+		assert.Equal(t, int32(codes.FailedPrecondition), replies[2].GetError().GetCode())
+		assert.Len(t, replies[2].GetError().GetDetails(), 1)
+		assert.Equal(t, "d", replies[3].GetId())
+		assert.Equal(t, int32(codes.InvalidArgument), replies[3].GetError().GetCode())
+	}
+}
+
+func TestGetDeliveryOrderPrice_Panic(t *testing.T) {
+	service := new(mockDOPCService)
+	conn, _ := startServer(t, service)
+	client := dopcv1.NewDeliveryPricingClient(conn)
+	service.On("CalculateDeliveryFee", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		panic("index out of range")
+	})
+
+	// The panic fails the call without leaking its value, and the server keeps serving.
+	_, err := client.GetDeliveryOrderPrice(context.Background(), validRequest())
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "Internal server error", st.Message())
+
+	stream, err := client.BatchGetDeliveryOrderPrices(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	assert.NoError(t, stream.Send(&dopcv1.BatchGetDeliveryOrderPricesRequest{Id: "a", Order: validRequest()}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestRateLimit(t *testing.T) {
+	service := new(mockDOPCService)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 2}, "client:")
+	conn, _ := startServer(t, service, WithLimiter(limiter))
+	client := dopcv1.NewDeliveryPricingClient(conn)
+	service.On("CalculateDeliveryFee", mock.Anything, mock.Anything).Return(models.PriceResponse{TotalPrice: 1190}, nil)
+
+	_, err := client.GetDeliveryOrderPrice(context.Background(), validRequest())
+	assert.NoError(t, err)
+
+	// Every message of a batch takes a token, and the stream ends once the bucket is empty.
+	stream, err := client.BatchGetDeliveryOrderPrices(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	assert.NoError(t, stream.Send(&dopcv1.BatchGetDeliveryOrderPricesRequest{Id: "a", Order: validRequest()}))
+	reply, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1190), reply.GetPrice().GetTotalPrice())
+	assert.NoError(t, stream.Send(&dopcv1.BatchGetDeliveryOrderPricesRequest{Id: "b", Order: validRequest()}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = client.GetDeliveryOrderPrice(context.Background(), validRequest())
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		assert.IsType(t, &errdetails.RetryInfo{}, st.Details()[0])
+	}
+
+	// Health checks are not limited.
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+// fakeKeyStore holds the identities by API key.
+type fakeKeyStore map[string]*auth.Identity
+
+func (s fakeKeyStore) Lookup(_ context.Context, apiKey string) (*auth.Identity, error) {
+	if apiKey == "unavailable" {
+		return nil, errors.New("store down")
+	}
+	identity, ok := s[apiKey]
+	if !ok {
+		return nil, auth.ErrUnknownKey
+	}
+	return identity, nil
+}
+
+func TestAuthentication(t *testing.T) {
+	service := new(mockDOPCService)
+	keys := fakeKeyStore{
+		"acme-key":  {KeyID: "acme", Scopes: []string{auth.ScopePricingRead}, VenuePatterns: []string{"home-assignment-*"}},
+		"other-key": {KeyID: "other", Scopes: []string{auth.ScopePricingRead}, VenuePatterns: []string{"other-*"}},
+		"admin-key": {KeyID: "admin", Scopes: []string{auth.ScopePricingAdmin}},
+	}
+	conn, _ := startServer(t, service, WithAuthenticator(NewAuthenticator(keys, "X-API-Key", nil)))
+	client := dopcv1.NewDeliveryPricingClient(conn)
+	service.On("CalculateDeliveryFee", mock.MatchedBy(func(ctx context.Context) bool {
+		identity, ok := auth.IdentityFromContext(ctx)
+		return ok && identity.KeyID == "acme"
+	}), mock.Anything).Return(models.PriceResponse{TotalPrice: 1190}, nil)
+	withKey := func(apiKey string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", apiKey)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{"Authenticated", withKey("acme-key"), codes.OK},
+		{"MissingKey", context.Background(), codes.Unauthenticated},
+		{"UnknownKey", withKey("unknown-key"), codes.Unauthenticated},
+		{"StoreUnavailable", withKey("unavailable"), codes.Unavailable},
+		{"InsufficientScope", withKey("admin-key"), codes.PermissionDenied},
+		{"VenueNotAllowed", withKey("other-key"), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetDeliveryOrderPrice(tt.ctx, validRequest())
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+
+	// Streams are authenticated when opened, and venues are checked for every order.
+	stream, err := client.BatchGetDeliveryOrderPrices(withKey("other-key"))
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	assert.NoError(t, stream.Send(&dopcv1.BatchGetDeliveryOrderPricesRequest{Id: "a", Order: validRequest()}))
+	reply, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(codes.PermissionDenied), reply.GetError().GetCode())
+
+	stream, err = client.BatchGetDeliveryOrderPrices(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Health checks are not authenticated.
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	conn, healthServer := startServer(t, new(mockDOPCService))
+
+	healthClient := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", "dopc.v1.DeliveryPricing"} {
+		resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus(), service)
+	}
+
+	// The service is reported as not serving once shutting down.
+	healthServer.Shutdown()
+	resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "dopc.v1.DeliveryPricing"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open reflection stream: %v", err)
+	}
+	assert.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	reply, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	var services []string
+	for _, service := range reply.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, "dopc.v1.DeliveryPricing")
+	assert.Contains(t, services, "grpc.health.v1.Health")
+}
@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Component status values reported by the readiness endpoint.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc reports whether a single dependency of the service is ready.
+// A nil error means the component is healthy.
+type CheckFunc func(ctx context.Context) error
+
+// ComponentStatus describes the outcome of a single readiness check.
+type ComponentStatus struct {
+	Status string `json:"status"`          // "up" or "down".
+	Error  string `json:"error,omitempty"` // Reason the component is down, if any.
+}
+
+// Report is the JSON body returned by the health endpoints.
+type Report struct {
+	Status     string                     `json:"status"`               // Overall status of the service.
+	Components map[string]ComponentStatus `json:"components,omitempty"` // Per-component readiness results.
+}
+
+// Checker keeps track of the registered readiness checks and the shutdown state of the service.
+type Checker struct {
+	mu           sync.RWMutex
+	checks       map[string]CheckFunc
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a new Checker. Each readiness check is bounded by the given timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  make(map[string]CheckFunc),
+		timeout: timeout,
+	}
+}
+
+// Register adds a named readiness check. Registering the same name twice replaces the previous check.
+func (c *Checker) Register(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// SetShuttingDown marks the service as shutting down so that readiness reports false
+// while in-flight requests are drained.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs every registered check concurrently and returns the aggregated report
+// together with a flag telling whether the service is ready to receive traffic.
+func (c *Checker) Ready(ctx context.Context) (Report, bool) {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]CheckFunc, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Run the checks in parallel so a slow dependency does not delay the others.
+	results := make([]ComponentStatus, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			if err := check(ctx); err != nil {
+				results[i] = ComponentStatus{Status: StatusDown, Error: err.Error()}
+				return
+			}
+			results[i] = ComponentStatus{Status: StatusUp}
+		}(i, check)
+	}
+	wg.Wait()
+
+	ready := !c.shuttingDown.Load()
+	report := Report{Components: make(map[string]ComponentStatus, len(names))}
+	for i, name := range names {
+		report.Components[name] = results[i]
+		if results[i].Status != StatusUp {
+			ready = false
+		}
+	}
+
+	switch {
+	case c.shuttingDown.Load():
+		report.Status = "shutting_down"
+	case ready:
+		report.Status = "ready"
+	default:
+		report.Status = "not_ready"
+	}
+	return report, ready
+}
+
+// Liveness handles GET /healthz. It only reports that the process is alive and able to serve HTTP.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, Report{Status: "ok"})
+}
+
+// Readiness handles GET /readyz. It responds with 200 when every component is up and
+// with 503 otherwise, including a per-component breakdown in both cases.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report, ready := c.Ready(r.Context())
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+// writeReport encodes the report as JSON with the given status code.
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeReport(t *testing.T, rec *httptest.ResponseRecorder) Report {
+	t.Helper()
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return report
+}
+
+func TestLiveness(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register("failing", func(ctx context.Context) error { return errors.New("boom") })
+
+	rec := httptest.NewRecorder()
+	checker.Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", decodeReport(t, rec).Status)
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       map[string]CheckFunc
+		shuttingDown bool
+		wantStatus   int
+		wantReport   string
+		wantDown     []string
+	}{
+		{
+			name: "All components up",
+			checks: map[string]CheckFunc{
+				"config":   func(ctx context.Context) error { return nil },
+				"upstream": func(ctx context.Context) error { return nil },
+			},
+			wantStatus: http.StatusOK,
+			wantReport: "ready",
+		},
+		{
+			name: "Upstream down",
+			checks: map[string]CheckFunc{
+				"config":   func(ctx context.Context) error { return nil },
+				"upstream": func(ctx context.Context) error { return errors.New("unreachable") },
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantReport: "not_ready",
+			wantDown:   []string{"upstream"},
+		},
+		{
+			name: "Shutting down",
+			checks: map[string]CheckFunc{
+				"config": func(ctx context.Context) error { return nil },
+			},
+			shuttingDown: true,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantReport:   "shutting_down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Second)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+			if tt.shuttingDown {
+				checker.SetShuttingDown()
+			}
+
+			rec := httptest.NewRecorder()
+			checker.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			report := decodeReport(t, rec)
+			assert.Equal(t, tt.wantReport, report.Status)
+			assert.Len(t, report.Components, len(tt.checks))
+			for _, name := range tt.wantDown {
+				assert.Equal(t, StatusDown, report.Components[name].Status)
+				assert.NotEmpty(t, report.Components[name].Error)
+			}
+		})
+	}
+}
+
+func TestReadiness_Timeout(t *testing.T) {
+	checker := NewChecker(10 * time.Millisecond)
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report, ready := checker.Ready(context.Background())
+
+	assert.False(t, ready)
+	assert.Equal(t, StatusDown, report.Components["slow"].Status)
+}
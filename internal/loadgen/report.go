@@ -0,0 +1,81 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Report summarizes a load test run.
+type Report struct {
+	Requests   int            `json:"requests"`       // Requests sent.
+	Errors     int            `json:"errors"`         // Requests that failed or did not answer 200.
+	ErrorRate  float64        `json:"error_rate"`     // Errors divided by requests.
+	Throughput float64        `json:"throughput_rps"` // Completed requests per second of the run.
+	Statuses   map[string]int `json:"statuses"`       // Requests by status code, or "error" when no response was received.
+	P50        time.Duration  `json:"p50_ns"`         // Median latency.
+	P95        time.Duration  `json:"p95_ns"`         // 95th percentile latency.
+	P99        time.Duration  `json:"p99_ns"`         // 99th percentile latency.
+	Max        time.Duration  `json:"max_ns"`         // Highest latency.
+	Elapsed    time.Duration  `json:"elapsed_ns"`     // Wall time of the run.
+}
+
+// newReport computes the report of the results of a run that took elapsed.
+func newReport(results []result, elapsed time.Duration) *Report {
+	report := &Report{Requests: len(results), Statuses: make(map[string]int), Elapsed: elapsed}
+	if len(results) == 0 {
+		return report
+	}
+
+	latencies := make([]time.Duration, len(results))
+	for i, r := range results {
+		latencies[i] = r.latency
+		if r.status == 0 {
+			report.Statuses["error"]++
+		} else {
+			report.Statuses[fmt.Sprint(r.status)]++
+		}
+		if r.status != 200 {
+			report.Errors++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	report.ErrorRate = float64(report.Errors) / float64(report.Requests)
+	report.Throughput = float64(report.Requests) / elapsed.Seconds()
+	report.P50 = percentile(latencies, 50)
+	report.P95 = percentile(latencies, 95)
+	report.P99 = percentile(latencies, 99)
+	report.Max = latencies[len(latencies)-1]
+	return report
+}
+
+// percentile returns the p-th percentile of sorted latencies using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// WriteText writes the report in a human readable form.
+func (r *Report) WriteText(w io.Writer) error {
+	statuses := make([]string, 0, len(r.Statuses))
+	for status := range r.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	_, err := fmt.Fprintf(w, "requests:   %d in %s\nthroughput: %.1f req/s\nerrors:     %d (%.2f%%)\nlatency:    p50 %s  p95 %s  p99 %s  max %s\n",
+		r.Requests, r.Elapsed.Round(time.Millisecond), r.Throughput, r.Errors, 100*r.ErrorRate,
+		r.P50.Round(time.Microsecond), r.P95.Round(time.Microsecond), r.P99.Round(time.Microsecond), r.Max.Round(time.Microsecond))
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if _, err := fmt.Fprintf(w, "status %s: %d\n", status, r.Statuses[status]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package loadgen
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReport(t *testing.T) {
+	results := make([]result, 0, 100)
+	for i := 1; i <= 100; i++ {
+		status := 200
+		if i%10 == 0 {
+			status = 500
+		}
+		if i == 50 {
+			status = 0
+		}
+		results = append(results, result{latency: time.Duration(i) * time.Millisecond, status: status})
+	}
+
+	report := newReport(results, 2*time.Second)
+	assert.Equal(t, 100, report.Requests)
+	assert.Equal(t, 10, report.Errors)
+	assert.Equal(t, map[string]int{"200": 90, "500": 9, "error": 1}, report.Statuses)
+	assert.InDelta(t, 0.1, report.ErrorRate, 1e-9)
+	assert.InDelta(t, 50, report.Throughput, 1e-9)
+	assert.Equal(t, 50*time.Millisecond, report.P50)
+	assert.Equal(t, 95*time.Millisecond, report.P95)
+	assert.Equal(t, 99*time.Millisecond, report.P99)
+	assert.Equal(t, 100*time.Millisecond, report.Max)
+
+	var out bytes.Buffer
+	assert.NoError(t, report.WriteText(&out))
+	assert.Contains(t, out.String(), "p99 99ms")
+	assert.Contains(t, out.String(), "status 500: 9")
+}
+
+func TestNewReport_Empty(t *testing.T) {
+	report := newReport(nil, time.Second)
+	assert.Equal(t, 0, report.Requests)
+	assert.Zero(t, report.P99)
+}
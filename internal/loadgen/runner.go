@@ -0,0 +1,89 @@
+package loadgen
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// result is the outcome of a single request.
+type result struct {
+	latency time.Duration
+	status  int // 0 when the request failed before a response was received.
+}
+
+// Run replays the scenario against its target at a fixed rate and reports the results. Requests
+// are started on schedule whatever the latency of the previous ones, so that a slow service
+// builds up concurrency instead of silently lowering the load (open-loop load generation).
+// Cancelling ctx stops starting new requests, but those in flight are left to complete, within
+// the timeout of the scenario, and are reported. Run returns once every started request has
+// completed.
+func Run(ctx context.Context, scenario *Scenario, client *http.Client) *Report {
+	generator := NewGenerator(scenario)
+	interval := time.Duration(float64(time.Second) / scenario.Rate)
+	total := int(scenario.Duration.Seconds() * scenario.Rate)
+
+	// In-flight requests are not aborted when ctx is cancelled, so that they are not reported
+	// as errors.
+	requestCtx := context.WithoutCancel(ctx)
+	var (
+		mu      sync.Mutex
+		results = make([]result, 0, total)
+		wg      sync.WaitGroup
+	)
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+loop:
+	for i := 0; i < total; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-ticker.C:
+			}
+		}
+
+		target := generator.Next()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := send(requestCtx, client, scenario, target)
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return newReport(results, time.Since(start))
+}
+
+// send makes one price request and measures its latency, including reading the body.
+func send(ctx context.Context, client *http.Client, scenario *Scenario, target string) result {
+	if scenario.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scenario.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return result{latency: time.Since(start)}
+	}
+	for key, value := range scenario.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result{latency: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return result{latency: time.Since(start), status: resp.StatusCode}
+}
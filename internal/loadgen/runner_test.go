@@ -0,0 +1,73 @@
+package loadgen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get("X-API-Key"))
+		assert.Equal(t, "/api/v1/delivery-order-price", r.URL.Path)
+		if requests.Add(1)%5 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"total_price": 1190}`))
+	}))
+	defer server.Close()
+
+	scenario := testScenario()
+	scenario.Target = server.URL
+	scenario.Rate = 200
+	scenario.Duration = 250 * time.Millisecond
+	scenario.Headers = map[string]string{"X-API-Key": "key"}
+
+	report := Run(context.Background(), scenario, server.Client())
+	assert.Equal(t, 50, report.Requests)
+	assert.Equal(t, 10, report.Errors)
+	assert.Equal(t, 40, report.Statuses["200"])
+	assert.Greater(t, report.P99, time.Duration(0))
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	scenario := testScenario()
+	scenario.Target = server.URL
+	scenario.Rate = 10
+	scenario.Duration = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	report := Run(ctx, scenario, server.Client())
+	assert.Less(t, report.Requests, 5)
+}
+
+func TestRun_CompletesInFlightRequestsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	scenario := testScenario()
+	scenario.Target = server.URL
+	scenario.Rate = 10
+	scenario.Duration = time.Hour
+
+	// The request in flight when the run is cancelled completes and is not counted as an error.
+	report := Run(ctx, scenario, server.Client())
+	assert.Equal(t, 1, report.Requests)
+	assert.Zero(t, report.ErrorRate)
+}
@@ -0,0 +1,141 @@
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario describes the price requests replayed against the service.
+type Scenario struct {
+	Target   string            `yaml:"target"`   // Base URL of the service under test.
+	Rate     float64           `yaml:"rate"`     // Requests started per second.
+	Duration time.Duration     `yaml:"duration"` // Time during which requests are started.
+	Timeout  time.Duration     `yaml:"timeout"`  // Upper bound for a single request.
+	Seed     uint64            `yaml:"seed"`     // Seed of the request generator, for reproducible runs.
+	Headers  map[string]string `yaml:"headers"`  // Headers sent with every request, such as an API key.
+	Venues   []VenueMix        `yaml:"venues"`   // Venues requested, picked by weight.
+
+	CartValue struct {
+		Min int `yaml:"min"` // Smallest cart value.
+		Max int `yaml:"max"` // Largest cart value.
+	} `yaml:"cart_value"` // Cart values are drawn uniformly in [min, max].
+}
+
+// VenueMix is a venue of the scenario with the distribution of the user locations around it.
+type VenueMix struct {
+	Slug   string  `yaml:"slug"`   // Venue slug.
+	Weight float64 `yaml:"weight"` // Relative share of the requests for this venue.
+	Lat    float64 `yaml:"lat"`    // Latitude of the center of the user locations.
+	Lon    float64 `yaml:"lon"`    // Longitude of the center of the user locations.
+	Radius float64 `yaml:"radius"` // User locations are drawn uniformly in this radius, in meters.
+}
+
+// LoadScenario reads and validates a scenario from a YAML file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read scenario: %w", err)
+	}
+	scenario := &Scenario{}
+	if err := yaml.Unmarshal(data, scenario); err != nil {
+		return nil, fmt.Errorf("could not decode scenario: %w", err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+// MaxRate is the highest rate a scenario may request. Beyond it, the interval between two
+// requests is too short for a ticker to keep the schedule.
+const MaxRate = 100_000
+
+// Validate checks that the scenario can be run.
+func (s *Scenario) Validate() error {
+	if _, err := url.ParseRequestURI(s.Target); err != nil {
+		return fmt.Errorf("invalid target: %w", err)
+	}
+	if s.Rate <= 0 {
+		return errors.New("rate must be positive")
+	}
+	if s.Rate > MaxRate {
+		return fmt.Errorf("rate must be at most %d requests per second", MaxRate)
+	}
+	if s.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if len(s.Venues) == 0 {
+		return errors.New("at least one venue is required")
+	}
+	for _, venue := range s.Venues {
+		if venue.Slug == "" || venue.Weight <= 0 || venue.Radius < 0 {
+			return fmt.Errorf("venue %q needs a slug, a positive weight and a non-negative radius", venue.Slug)
+		}
+	}
+	if s.CartValue.Min <= 0 || s.CartValue.Max < s.CartValue.Min {
+		return errors.New("cart_value needs 0 < min <= max")
+	}
+	return nil
+}
+
+// Generator produces the request URLs of a scenario. The sequence only depends on the seed.
+// It is not safe for concurrent use.
+type Generator struct {
+	scenario    *Scenario
+	rand        *rand.Rand
+	totalWeight float64
+}
+
+// NewGenerator creates a Generator for the scenario.
+func NewGenerator(scenario *Scenario) *Generator {
+	g := &Generator{scenario: scenario, rand: rand.New(rand.NewPCG(scenario.Seed, scenario.Seed))}
+	for _, venue := range scenario.Venues {
+		g.totalWeight += venue.Weight
+	}
+	return g
+}
+
+// earthRadius is the Earth radius in meters, as used by the distance calculation of the service.
+const earthRadius = 6371000
+
+// Next returns the URL of the next price request.
+func (g *Generator) Next() string {
+	venue := g.pickVenue()
+
+	// Draw a point uniformly in the disc around the venue center; the square root spreads the
+	// points evenly over the area instead of clustering them at the center.
+	distance := venue.Radius * math.Sqrt(g.rand.Float64())
+	bearing := 2 * math.Pi * g.rand.Float64()
+	lat := venue.Lat + distance*math.Cos(bearing)/earthRadius*180/math.Pi
+	lon := venue.Lon + distance*math.Sin(bearing)/(earthRadius*math.Cos(venue.Lat*math.Pi/180))*180/math.Pi
+
+	cartValue := g.scenario.CartValue.Min + g.rand.IntN(g.scenario.CartValue.Max-g.scenario.CartValue.Min+1)
+
+	query := url.Values{
+		"venue_slug": {venue.Slug},
+		"cart_value": {strconv.Itoa(cartValue)},
+		"user_lat":   {strconv.FormatFloat(lat, 'f', 6, 64)},
+		"user_lon":   {strconv.FormatFloat(lon, 'f', 6, 64)},
+	}
+	return g.scenario.Target + "/api/v1/delivery-order-price?" + query.Encode()
+}
+
+// pickVenue picks a venue with a probability proportional to its weight.
+func (g *Generator) pickVenue() VenueMix {
+	pick := g.rand.Float64() * g.totalWeight
+	for _, venue := range g.scenario.Venues {
+		if pick < venue.Weight {
+			return venue
+		}
+		pick -= venue.Weight
+	}
+	return g.scenario.Venues[len(g.scenario.Venues)-1]
+}
@@ -0,0 +1,107 @@
+package loadgen
+
+import (
+	"backend-wolt-go/internal/utils"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testScenario() *Scenario {
+	scenario := &Scenario{
+		Target:   "http://localhost:8000",
+		Rate:     100,
+		Duration: time.Second,
+		Seed:     42,
+		Venues: []VenueMix{
+			{Slug: "popular", Weight: 3, Lat: 60.17, Lon: 24.93, Radius: 1000},
+			{Slug: "quiet", Weight: 1, Lat: 60.2, Lon: 24.9, Radius: 0},
+		},
+	}
+	scenario.CartValue.Min, scenario.CartValue.Max = 500, 1500
+	return scenario
+}
+
+func TestLoadScenario(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	content := `
+target: http://localhost:8000
+rate: 10
+duration: 5s
+venues:
+  - {slug: venue, weight: 1, lat: 60.17, lon: 24.93, radius: 500}
+cart_value: {min: 100, max: 200}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write scenario: %v", err)
+	}
+
+	scenario, err := LoadScenario(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, scenario.Duration)
+	assert.Equal(t, "venue", scenario.Venues[0].Slug)
+
+	_, err = LoadScenario(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestScenario_Validate(t *testing.T) {
+	assert.NoError(t, testScenario().Validate())
+
+	for name, mutate := range map[string]func(*Scenario){
+		"target":     func(s *Scenario) { s.Target = "not a url" },
+		"rate":       func(s *Scenario) { s.Rate = 0 },
+		"max_rate":   func(s *Scenario) { s.Rate = 2e9 },
+		"duration":   func(s *Scenario) { s.Duration = 0 },
+		"venues":     func(s *Scenario) { s.Venues = nil },
+		"weight":     func(s *Scenario) { s.Venues[0].Weight = 0 },
+		"cart_value": func(s *Scenario) { s.CartValue.Max = 100 },
+	} {
+		scenario := testScenario()
+		mutate(scenario)
+		assert.Error(t, scenario.Validate(), name)
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	scenario := testScenario()
+	generator := NewGenerator(scenario)
+
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		query := parseQuery(t, generator.Next())
+		counts[query.Get("venue_slug")]++
+
+		venue := scenario.Venues[0]
+		if query.Get("venue_slug") == "quiet" {
+			venue = scenario.Venues[1]
+		}
+		lat, _ := strconv.ParseFloat(query.Get("user_lat"), 64)
+		lon, _ := strconv.ParseFloat(query.Get("user_lon"), 64)
+		assert.LessOrEqual(t, utils.CalculateDistance(venue.Lat, venue.Lon, lat, lon), int(venue.Radius)+1)
+
+		cartValue, _ := strconv.Atoi(query.Get("cart_value"))
+		assert.True(t, cartValue >= 500 && cartValue <= 1500, cartValue)
+	}
+	assert.InDelta(t, 0.75, float64(counts["popular"])/2000, 0.05)
+
+	// The same seed replays the same requests.
+	first, second := NewGenerator(scenario), NewGenerator(scenario)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first.Next(), second.Next())
+	}
+}
+
+func parseQuery(t *testing.T, rawURL string) url.Values {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", rawURL, err)
+	}
+	return parsed.Query()
+}
@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Redacted replaces the value of sensitive attributes and query parameters.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys lists attribute keys and query parameters holding user coordinates.
+var sensitiveKeys = map[string]bool{
+	"user_lat": true,
+	"user_lon": true,
+	"lat":      true,
+	"lon":      true,
+}
+
+// contextKey is the type of the context key holding the request-scoped logger.
+type contextKey struct{}
+
+// fieldsKey is the type of the context key holding the attributes added during a request.
+type fieldsKey struct{}
+
+// requestFields collects attributes added by inner handlers so that the completion log line,
+// written by the outermost middleware, includes them as well.
+type requestFields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// New creates a JSON logger writing to w at the given level ("debug", "info", "warn" or "error").
+// Attributes holding user coordinates are always redacted.
+func New(level string, w io.Writer) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level == "" {
+		level = "info"
+	}
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: redactAttr,
+	})
+	return slog.New(handler), nil
+}
+
+// redactAttr hides the value of sensitive attributes.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+// RedactQuery returns the encoded query string with the values of sensitive parameters replaced.
+func RedactQuery(query url.Values) string {
+	redacted := make(url.Values, len(query))
+	for key, values := range query {
+		if sensitiveKeys[strings.ToLower(key)] {
+			redacted[key] = []string{Redacted}
+			continue
+		}
+		redacted[key] = values
+	}
+	return redacted.Encode()
+}
+
+// WithLogger returns a copy of ctx carrying the logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or the default logger if there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// WithAttrs returns a copy of ctx whose logger carries the attributes. Inside Middleware the
+// attributes are also added to the request completion log line.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if fields, ok := ctx.Value(fieldsKey{}).(*requestFields); ok {
+		fields.mu.Lock()
+		fields.attrs = append(fields.attrs, attrs...)
+		fields.mu.Unlock()
+	}
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// Middleware attaches a logger carrying the request ID to the request context and logs every
+// completed request. It must run after chi's middleware.RequestID.
+func Middleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := middleware.GetReqID(r.Context())
+			if requestID != "" {
+				w.Header().Set(middleware.RequestIDHeader, requestID)
+			}
+
+			reqLogger := logger.With(slog.String("request_id", requestID))
+			fields := &requestFields{}
+			ctx := context.WithValue(WithLogger(r.Context(), reqLogger), fieldsKey{}, fields)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("query", RedactQuery(r.URL.Query())),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+			}
+			fields.mu.Lock()
+			attrs = append(attrs, fields.attrs...)
+			fields.mu.Unlock()
+			reqLogger.LogAttrs(r.Context(), level, "request completed", attrs...)
+		})
+	}
+}
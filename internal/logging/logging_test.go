@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New("warn", &buf)
+	assert.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "shown")
+}
+
+func TestNew_InvalidLevel(t *testing.T) {
+	_, err := New("verbose", &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestNew_RedactsCoordinates(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New("debug", &buf)
+	assert.NoError(t, err)
+
+	logger.Debug("quote", slog.Float64("lat", 60.17094), slog.Float64("lon", 24.93087), slog.String("venue_slug", "abc"))
+
+	assert.NotContains(t, buf.String(), "60.17094")
+	assert.NotContains(t, buf.String(), "24.93087")
+	assert.Contains(t, buf.String(), `"venue_slug":"abc"`)
+}
+
+func TestRedactQuery(t *testing.T) {
+	query := url.Values{"venue_slug": {"abc"}, "user_lat": {"60.17"}, "user_lon": {"24.93"}}
+
+	redacted := RedactQuery(query)
+
+	assert.Equal(t, "user_lat=%5BREDACTED%5D&user_lon=%5BREDACTED%5D&venue_slug=abc", redacted)
+	assert.Equal(t, "60.17", query.Get("user_lat"), "the original query must not be modified")
+}
+
+func TestFromContext_Default(t *testing.T) {
+	assert.Equal(t, slog.Default(), FromContext(context.Background()))
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New("info", &buf)
+	assert.NoError(t, err)
+
+	var handlerLogger *slog.Logger
+	handler := middleware.RequestID(Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerLogger = FromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/delivery-order-price?venue_slug=abc&user_lat=60.17&user_lon=24.93", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.NotNil(t, handlerLogger)
+	assert.Equal(t, "req-123", rec.Header().Get(middleware.RequestIDHeader))
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, float64(http.StatusTeapot), entry["status"])
+	assert.NotContains(t, buf.String(), "60.17")
+}
+
+func TestWithAttrs_AddedToCompletionLine(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New("info", &buf)
+	assert.NoError(t, err)
+
+	handler := Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithAttrs(r.Context(), slog.String("key_id", "key-1"))
+		FromContext(ctx).Info("inner")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, string(lines[0]), `"key_id":"key-1"`)
+		assert.Contains(t, string(lines[1]), `"key_id":"key-1"`)
+	}
+}
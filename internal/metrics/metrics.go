@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dopc"
+
+// Metrics holds the Prometheus collectors exported by the service.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	upstreamDuration    *prometheus.HistogramVec
+	upstreamErrors      *prometheus.CounterVec
+	cacheRequests       *prometheus.CounterVec
+	cacheFreshness      *prometheus.CounterVec
+	outOfRangeRejection *prometheus.CounterVec
+	apiKeyRequests      *prometheus.CounterVec
+	deliveryFee         prometheus.Histogram
+	deliveryDistance    prometheus.Histogram
+}
+
+// New creates a Metrics instance with its own registry, including the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Latency of venue API calls by endpoint (static or dynamic).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_errors_total",
+			Help:      "Number of failed venue API calls by endpoint (static or dynamic).",
+		}, []string{"endpoint"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Number of venue cache lookups by cache name and result (hit or miss).",
+		}, []string{"cache", "result"}),
+		cacheFreshness: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "venue_cache_freshness_total",
+			Help:      "Number of venue API lookups by endpoint and freshness state (fresh, stale, revalidated or miss).",
+		}, []string{"endpoint", "state"}),
+		outOfRangeRejection: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "out_of_range_rejections_total",
+			Help:      "Number of price requests rejected because the user is outside the delivery range, by venue.",
+		}, []string{"venue"}),
+		apiKeyRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_key_requests_total",
+			Help:      "Number of authenticated requests by API key ID and status code.",
+		}, []string{"key_id", "status"}),
+		deliveryFee: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "delivery_fee",
+			Help:      "Distribution of computed delivery fees in the smallest currency unit.",
+			Buckets:   prometheus.LinearBuckets(100, 100, 15),
+		}),
+		deliveryDistance: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "delivery_distance_meters",
+			Help:      "Distribution of computed delivery distances in meters.",
+			Buckets:   prometheus.LinearBuckets(250, 250, 12),
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.upstreamDuration,
+		m.upstreamErrors,
+		m.cacheRequests,
+		m.cacheFreshness,
+		m.outOfRangeRejection,
+		m.apiKeyRequests,
+		m.deliveryFee,
+		m.deliveryDistance,
+	)
+	return m
+}
+
+// Registry returns the registry holding the service collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware records the count and latency of every HTTP request, labelled by the chi route pattern
+// rather than the raw path so that query strings and slugs do not explode the label cardinality.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{"route": route, "method": r.Method, "status": strconv.Itoa(status)}
+		m.httpRequests.With(labels).Inc()
+		m.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// ObserveUpstream records the latency and outcome of a call to the venue API endpoint.
+func (m *Metrics) ObserveUpstream(endpoint string, duration time.Duration, err error) {
+	m.upstreamDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if err != nil {
+		m.upstreamErrors.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// ObserveCache records a lookup in the named cache.
+func (m *Metrics) ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveFreshness records the freshness state of a venue API lookup served by the HTTP cache.
+func (m *Metrics) ObserveFreshness(endpoint, state string) {
+	m.cacheFreshness.WithLabelValues(endpoint, state).Inc()
+}
+
+// ObserveQuote records the distance and delivery fee of a successfully priced order.
+func (m *Metrics) ObserveQuote(distance, fee int) {
+	m.deliveryDistance.Observe(float64(distance))
+	m.deliveryFee.Observe(float64(fee))
+}
+
+// ObserveOutOfRange records a price request rejected because the user is too far from the venue.
+func (m *Metrics) ObserveOutOfRange(venueSlug string) {
+	m.outOfRangeRejection.WithLabelValues(venueSlug).Inc()
+}
+
+// ObserveKeyRequest records a request authenticated with the API key.
+func (m *Metrics) ObserveKeyRequest(keyID string, status int) {
+	m.apiKeyRequests.WithLabelValues(keyID, strconv.Itoa(status)).Inc()
+}
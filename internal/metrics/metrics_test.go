@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_RecordsRoutePattern(t *testing.T) {
+	m := New()
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/venues/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	for _, slug := range []string{"a", "b"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/venues/"+slug, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("/venues/{slug}", http.MethodGet, "418")))
+}
+
+func TestObserveUpstream(t *testing.T) {
+	m := New()
+	m.ObserveUpstream("static", 10*time.Millisecond, nil)
+	m.ObserveUpstream("dynamic", 10*time.Millisecond, errors.New("boom"))
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.upstreamErrors.WithLabelValues("static")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.upstreamErrors.WithLabelValues("dynamic")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.upstreamDuration))
+}
+
+func TestPricingMetrics(t *testing.T) {
+	m := New()
+	m.ObserveQuote(177, 190)
+	m.ObserveOutOfRange("venue")
+	m.ObserveOutOfRange("venue")
+	m.ObserveCache("venue", true)
+	m.ObserveCache("venue", false)
+	m.ObserveKeyRequest("key-1", http.StatusOK)
+	m.ObserveFreshness("dynamic", "stale")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.outOfRangeRejection.WithLabelValues("venue")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.cacheRequests.WithLabelValues("venue", "hit")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.deliveryFee))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.apiKeyRequests.WithLabelValues("key-1", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.cacheFreshness.WithLabelValues("dynamic", "stale")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.ObserveQuote(177, 190)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), "dopc_delivery_fee_count 1"))
+}
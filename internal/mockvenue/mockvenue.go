@@ -0,0 +1,215 @@
+package mockvenue
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Endpoint names of the venue API.
+const (
+	EndpointStatic  = "static"
+	EndpointDynamic = "dynamic"
+)
+
+// Venue holds the venue API responses of a venue, in the format of the Home Assignment API.
+type Venue struct {
+	Static  []byte
+	Dynamic []byte
+}
+
+// Fault describes a failure injected into the responses of the mock venue API.
+type Fault struct {
+	Endpoint    string        // Endpoint the fault applies to; empty for both.
+	Latency     time.Duration // Delay before answering.
+	Status      int           // Status code answered instead of the venue data, if set.
+	Malformed   bool          // Answer with a truncated JSON body.
+	Probability float64       // Fraction of the matching requests affected; 0 affects all of them.
+}
+
+// API is a mock of the Home Assignment venue API serving /{slug}/static and /{slug}/dynamic from
+// fixtures, with injectable latency, error statuses and malformed payloads. Unknown venues are
+// answered with 404. Responses carry an ETag, and matching If-None-Match requests get 304.
+// It is safe for concurrent use.
+type API struct {
+	mu       sync.Mutex
+	venues   map[string]Venue
+	faults   map[string]Fault // Faults by venue slug; the empty slug applies to every venue.
+	requests map[string]int   // Requests by "<slug>/<endpoint>".
+	router   chi.Router
+}
+
+// New creates an API serving the given venues by slug.
+func New(venues map[string]Venue) *API {
+	a := &API{
+		venues:   make(map[string]Venue, len(venues)),
+		faults:   make(map[string]Fault),
+		requests: make(map[string]int),
+	}
+	for venueSlug, venue := range venues {
+		a.venues[venueSlug] = venue
+	}
+
+	a.router = chi.NewRouter()
+	a.router.Get("/{slug}/{endpoint}", a.serveVenue)
+	return a
+}
+
+// LoadDir reads the venues of a fixture directory laid out as <slug>/static.json and
+// <slug>/dynamic.json. Subdirectories missing either file are skipped.
+func LoadDir(dir string) (map[string]Venue, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read fixture directory: %w", err)
+	}
+
+	venues := make(map[string]Venue, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		static, err := os.ReadFile(filepath.Join(dir, entry.Name(), "static.json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read static fixture of %s: %w", entry.Name(), err)
+		}
+		dynamic, err := os.ReadFile(filepath.Join(dir, entry.Name(), "dynamic.json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read dynamic fixture of %s: %w", entry.Name(), err)
+		}
+		venues[entry.Name()] = Venue{Static: static, Dynamic: dynamic}
+	}
+	return venues, nil
+}
+
+// NewServer starts an httptest server for api that is closed when the test ends.
+func NewServer(tb testing.TB, api *API) *httptest.Server {
+	tb.Helper()
+	server := httptest.NewServer(api)
+	tb.Cleanup(server.Close)
+	return server
+}
+
+// SetVenue adds or replaces a venue.
+func (a *API) SetVenue(venueSlug string, venue Venue) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.venues[venueSlug] = venue
+}
+
+// RemoveVenue removes a venue, so that it is answered with 404.
+func (a *API) RemoveVenue(venueSlug string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.venues, venueSlug)
+}
+
+// SetFault injects the fault into the responses for the venue, replacing any previous fault.
+// An empty slug injects it for every venue without a fault of its own.
+func (a *API) SetFault(venueSlug string, fault Fault) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults[venueSlug] = fault
+}
+
+// ClearFaults removes every injected fault.
+func (a *API) ClearFaults() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	clear(a.faults)
+}
+
+// Requests returns the number of requests received for the endpoint of the venue.
+func (a *API) Requests(venueSlug, endpoint string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requests[venueSlug+"/"+endpoint]
+}
+
+// ServeHTTP serves the venue API.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.router.ServeHTTP(w, r)
+}
+
+// serveVenue answers a venue API request, applying the fault of the venue if it matches.
+func (a *API) serveVenue(w http.ResponseWriter, r *http.Request) {
+	venueSlug, endpoint := chi.URLParam(r, "slug"), chi.URLParam(r, "endpoint")
+	if endpoint != EndpointStatic && endpoint != EndpointDynamic {
+		http.NotFound(w, r)
+		return
+	}
+
+	a.mu.Lock()
+	a.requests[venueSlug+"/"+endpoint]++
+	venue, found := a.venues[venueSlug]
+	fault, faulty := a.faults[venueSlug]
+	if !faulty {
+		fault, faulty = a.faults[""]
+	}
+	a.mu.Unlock()
+
+	if faulty && fault.applies(endpoint) {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 {
+			http.Error(w, http.StatusText(fault.Status), fault.Status)
+			return
+		}
+		if fault.Malformed {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"venue_raw": {`))
+			return
+		}
+	}
+
+	if !found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "venue not found"}`))
+		return
+	}
+
+	body := venue.Static
+	if endpoint == EndpointDynamic {
+		body = venue.Dynamic
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// applies reports whether the fault affects this request to the endpoint.
+func (f Fault) applies(endpoint string) bool {
+	if f.Endpoint != "" && f.Endpoint != endpoint {
+		return false
+	}
+	return f.Probability <= 0 || rand.Float64() < f.Probability
+}
@@ -0,0 +1,141 @@
+package mockvenue
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testVenue = Venue{
+	Static:  []byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`),
+	Dynamic: []byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000}}}`),
+}
+
+// get requests the path from the server and returns the status and body.
+func get(t *testing.T, url string, header http.Header) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestAPI_ServesVenues(t *testing.T) {
+	api := New(map[string]Venue{"venue": testVenue})
+	server := NewServer(t, api)
+
+	status, body := get(t, server.URL+"/venue/static", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, string(testVenue.Static), body)
+
+	status, body = get(t, server.URL+"/venue/dynamic", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, string(testVenue.Dynamic), body)
+
+	status, _ = get(t, server.URL+"/unknown/static", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = get(t, server.URL+"/venue/other", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	assert.Equal(t, 1, api.Requests("venue", EndpointStatic))
+	assert.Equal(t, 1, api.Requests("unknown", EndpointStatic))
+}
+
+func TestAPI_ConditionalRequests(t *testing.T) {
+	server := NewServer(t, New(map[string]Venue{"venue": testVenue}))
+
+	resp, err := http.Get(server.URL + "/venue/dynamic")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	status, _ := get(t, server.URL+"/venue/dynamic", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, status)
+}
+
+func TestAPI_Faults(t *testing.T) {
+	api := New(map[string]Venue{"venue": testVenue, "other": testVenue})
+	server := NewServer(t, api)
+
+	api.SetFault("venue", Fault{Endpoint: EndpointDynamic, Status: http.StatusServiceUnavailable})
+	status, _ := get(t, server.URL+"/venue/static", nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = get(t, server.URL+"/venue/dynamic", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	status, _ = get(t, server.URL+"/other/dynamic", nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	// A fault for the empty slug applies to every venue.
+	api.ClearFaults()
+	api.SetFault("", Fault{Malformed: true})
+	status, body := get(t, server.URL+"/other/static", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"venue_raw": {`, body)
+
+	api.ClearFaults()
+	api.SetFault("", Fault{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	status, _ = get(t, server.URL+"/venue/static", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	api.RemoveVenue("venue")
+	api.ClearFaults()
+	status, _ = get(t, server.URL+"/venue/static", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestAPI_LatencyHonorsCancellation(t *testing.T) {
+	api := New(map[string]Venue{"venue": testVenue})
+	api.SetFault("", Fault{Latency: time.Minute})
+	server := NewServer(t, api)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/venue/static", nil)
+	_, err := http.DefaultClient.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"venue/static.json":   string(testVenue.Static),
+		"venue/dynamic.json":  string(testVenue.Dynamic),
+		"partial/static.json": string(testVenue.Static),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create fixture directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+	}
+
+	venues, err := LoadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Venue{"venue": testVenue}, venues)
+
+	_, err = LoadDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
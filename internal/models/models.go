@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // VenueStaticResponse represents the static information of a venue,
 // including its geographical location.
 type VenueStaticResponse struct {
@@ -7,7 +9,12 @@ type VenueStaticResponse struct {
 		Location struct {
 			Coordinates []float64 `json:"coordinates"` // Coordinates of the venue [longitude, latitude].
 		} `json:"location"`
+		Timezone     string              `json:"timezone,omitempty"`      // IANA time zone of the venue, e.g. "Europe/Helsinki".
+		OpeningHours map[string][]string `json:"opening_hours,omitempty"` // Delivery hours by lower case weekday, e.g. "monday": ["10:00-22:00"].
 	} `json:"venue_raw"`
+
+	Stale     bool      `json:"-"` // Set when served from a snapshot because the venue API failed.
+	FetchedAt time.Time `json:"-"` // Time the data was fetched from the venue API.
 }
 
 // VenueDynamicResponse represents the dynamic information of a venue,
@@ -15,7 +22,8 @@ type VenueStaticResponse struct {
 type VenueDynamicResponse struct {
 	VenueRaw *struct {
 		DeliverySpecs struct {
-			OrderMinimumNoSurcharge int `json:"order_minimum_no_surcharge"` // Minimum order value to avoid surcharge.
+			OrderMinimumNoSurcharge int   `json:"order_minimum_no_surcharge"` // Minimum order value to avoid surcharge.
+			DeliveryEnabled         *bool `json:"delivery_enabled,omitempty"` // Whether the venue currently delivers; unset means it does.
 			DeliveryPricing         struct {
 				BasePrice      int `json:"base_price"` // Base delivery price.
 				DistanceRanges []struct {
@@ -27,6 +35,9 @@ type VenueDynamicResponse struct {
 			} `json:"delivery_pricing"`
 		} `json:"delivery_specs"`
 	} `json:"venue_raw"`
+
+	Stale     bool      `json:"-"` // Set when served from a snapshot because the venue API failed.
+	FetchedAt time.Time `json:"-"` // Time the data was fetched from the venue API.
 }
 
 // PriceResponse represents the response for delivery pricing calculations.
@@ -35,9 +46,25 @@ type PriceResponse struct {
 	SmallOrderSurcharge int `json:"small_order_surcharge"` // Surcharge for orders below the minimum value.
 	CartValue           int `json:"cart_value"`           // Value of the cart.
 	Delivery            struct {
-		Fee      int `json:"fee"`      // Calculated delivery fee.
-		Distance int `json:"distance"` // Distance between venue and user in meters.
+		Fee      int      `json:"fee"`             // Calculated delivery fee.
+		Distance int      `json:"distance"`        // Distance between venue and user in meters.
+		Zone     string   `json:"zone,omitempty"`  // Delivery zone used for the fee, if any.
+		Rules    []string `json:"rules,omitempty"` // Time-dependent fee rules applied to the fee, if any.
+		Mode     string   `json:"mode,omitempty"`  // Courier transport mode the fee was computed for, if modes are configured.
 	} `json:"delivery"`
+	Stale        bool       `json:"stale,omitempty"`         // Set when the price was computed from stale venue data.
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"` // Delivery time the price applies to, for scheduled orders.
+
+	EstimatedDeliveryMinutes *DeliveryEstimate `json:"estimated_delivery_minutes,omitempty"` // Estimated delivery time of orders as soon as possible.
+
+	QuoteID string `json:"quote_id,omitempty"` // Identifier of the quote in the quote log, if enabled.
+}
+
+// DeliveryEstimate represents the estimated time until an order is delivered, in minutes.
+type DeliveryEstimate struct {
+	Expected int `json:"expected"` // Most likely delivery time.
+	Min      int `json:"min"`      // Lower bound of the delivery time.
+	Max      int `json:"max"`      // Upper bound of the delivery time.
 }
 
 // OrderInfo represents the information about an order required for delivery fee calculations.
@@ -46,14 +73,17 @@ type OrderInfo struct {
 	Lat       float64 `json:"lat"`       // Latitude of the user's location.
 	Lon       float64 `json:"lon"`       // Longitude of the user's location.
 	CartValue int     `json:"cart_value"` // Value of the user's cart.
+
+	DeliveryTime time.Time `json:"delivery_time"` // Requested delivery time of a scheduled order, zero for as soon as possible.
+	CourierMode  string    `json:"courier_mode"`  // Courier transport mode requested by the client, empty to pick the cheapest.
 }
 
 // DistanceRange represents a range of distances and associated pricing factors.
 type DistanceRange struct {
-	Min int     `json:"min"` // Minimum distance range (inclusive).
-	Max int     `json:"max"` // Maximum distance range (exclusive).
-	A   int     `json:"a"`   // Constant factor for pricing.
-	B   float64 `json:"b"`   // Multiplier factor for pricing.
+	Min int     `json:"min" yaml:"min"` // Minimum distance range (inclusive).
+	Max int     `json:"max" yaml:"max"` // Maximum distance range (exclusive).
+	A   int     `json:"a" yaml:"a"`     // Constant factor for pricing.
+	B   float64 `json:"b" yaml:"b"`     // Multiplier factor for pricing.
 }
 
 // ServerError represents an error message to be sent to the client.
@@ -64,10 +94,209 @@ type ServerError struct {
 // Config represents the configuration settings for the server and API.
 type Config struct {
 	Server struct {
-		Port int `yaml:"port"` // Port number for the server to listen on.
+		Port            int           `yaml:"port"`             // Port number for the server to listen on.
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // Time allowed for in-flight requests to finish on shutdown.
 	} `yaml:"server"`
 
+	GRPC struct {
+		Enabled bool `yaml:"enabled"` // Whether the gRPC API is served alongside the REST endpoint.
+		Port    int  `yaml:"port"`    // Port number for the gRPC server to listen on.
+	} `yaml:"grpc"`
+
 	API struct {
-		BaseURL string `yaml:"base_url"` // Base URL for external API calls.
+		BaseURL string        `yaml:"base_url"` // Base URL for external API calls.
+		Timeout time.Duration `yaml:"timeout"`  // Upper bound for a single venue API call; 0 disables it.
 	} `yaml:"api"`
+
+	Health struct {
+		CheckTimeout time.Duration `yaml:"check_timeout"` // Upper bound for all readiness checks of a single probe.
+	} `yaml:"health"`
+
+	Logging struct {
+		Level string `yaml:"level"` // Minimum log level: debug, info, warn or error.
+	} `yaml:"logging"`
+
+	Tracing TracingConfig `yaml:"tracing"`
+
+	OpenAPI struct {
+		DocsUI        bool   `yaml:"docs_ui"`         // Whether an interactive documentation page is served at /docs.
+		DocsAssetsDir string `yaml:"docs_assets_dir"` // Directory holding the Swagger UI files of the documentation page.
+	} `yaml:"openapi"`
+
+	RateLimit struct {
+		Enabled  bool            `yaml:"enabled"`  // Whether rate limiting is enforced.
+		Client   RateLimitConfig `yaml:"client"`   // Limit applied to each client on the price endpoint.
+		Upstream RateLimitConfig `yaml:"upstream"` // Limit applied to venue API calls per venue slug.
+	} `yaml:"rate_limit"`
+
+	Auth struct {
+		Enabled bool   `yaml:"enabled"`  // Whether the price endpoint requires an API key.
+		Header  string `yaml:"header"`   // Header carrying the API key.
+		KeyFile string `yaml:"key_file"` // Path to the YAML file listing the API keys.
+
+		JWT struct {
+			Enabled         bool          `yaml:"enabled"`          // Whether bearer tokens are accepted.
+			JWKSURL         string        `yaml:"jwks_url"`         // URL of the identity provider's JWKS document.
+			Issuer          string        `yaml:"issuer"`           // Expected "iss" claim.
+			Audience        string        `yaml:"audience"`         // Expected "aud" claim.
+			RefreshInterval time.Duration `yaml:"refresh_interval"` // Maximum age of the cached signing keys.
+			Leeway          time.Duration `yaml:"leeway"`           // Allowed clock skew when checking expiry.
+		} `yaml:"jwt"`
+	} `yaml:"auth"`
+
+	Snapshot struct {
+		Enabled bool          `yaml:"enabled"` // Whether venue data is persisted and served when the venue API fails.
+		Dir     string        `yaml:"dir"`     // Directory holding one JSON snapshot per venue.
+		MaxAge  time.Duration `yaml:"max_age"` // Maximum age of a snapshot served in place of fresh data.
+	} `yaml:"snapshot"`
+
+	HTTPCache struct {
+		Enabled                     bool          `yaml:"enabled"`                        // Whether venue API responses are cached.
+		DefaultMaxAge               time.Duration `yaml:"default_max_age"`                // Freshness lifetime when the venue API sends no max-age.
+		DefaultStaleWhileRevalidate time.Duration `yaml:"default_stale_while_revalidate"` // Stale window when the venue API sends no stale-while-revalidate.
+	} `yaml:"http_cache"`
+
+	Prefetch PrefetchConfig `yaml:"prefetch"`
+
+	VenueSource struct {
+		Chain      []string               `yaml:"chain"`       // Venue sources tried in order: "http", "file" or "config".
+		FixtureDir string                 `yaml:"fixture_dir"` // Directory of the "file" source, holding <slug>/static.json and <slug>/dynamic.json.
+		Venues     map[string]VenueConfig `yaml:"venues"`      // Venues of the "config" source by slug.
+	} `yaml:"venue_source"`
+
+	DeliveryZones DeliveryZonesConfig `yaml:"delivery_zones"`
+
+	Exclusions ExclusionsConfig `yaml:"exclusions"`
+
+	Availability AvailabilityConfig `yaml:"availability"`
+
+	FeeRules []FeeRuleConfig `yaml:"fee_rules"`
+
+	ETA ETAConfig `yaml:"eta"`
+
+	CourierModes []CourierModeConfig `yaml:"courier_modes"`
+
+	QuoteLog QuoteLogConfig `yaml:"quote_log"`
+
+	Slots struct {
+		Interval time.Duration `yaml:"interval"` // Length of the delivery slots listed by the slots endpoint.
+		Horizon  time.Duration `yaml:"horizon"`  // How far ahead delivery slots are listed.
+	} `yaml:"slots"`
+}
+
+// QuoteLogConfig represents the audit log of the price quotes.
+type QuoteLogConfig struct {
+	Enabled    bool   `yaml:"enabled"`     // Whether every price calculation is recorded.
+	File       string `yaml:"file"`        // JSON Lines file the quotes are appended to.
+	MaxSizeMB  int    `yaml:"max_size_mb"` // Size in megabytes at which the file is rotated; 0 for 100.
+	MaxBackups int    `yaml:"max_backups"` // Number of rotated files kept; 0 keeps all of them.
+}
+
+// FeeRuleConfig represents a time-dependent modifier of the delivery fee, such as a surge.
+// While the rule is active, the fee becomes fee * multiplier + surcharge.
+type FeeRuleConfig struct {
+	Name       string              `yaml:"name"`        // Name of the rule, reported in the price response.
+	VenueSlugs []string            `yaml:"venue_slugs"` // Venues the rule applies to; empty for every venue.
+	Timezone   string              `yaml:"timezone"`    // IANA time zone of the hours, UTC when empty.
+	Hours      map[string][]string `yaml:"hours"`       // "HH:MM-HH:MM" intervals by lower case weekday during which the rule is active.
+	Multiplier float64             `yaml:"multiplier"`  // Factor applied to the delivery fee; 0 leaves it unchanged.
+	Surcharge  int                 `yaml:"surcharge"`   // Amount added to the delivery fee.
+}
+
+// AvailabilityConfig represents the configured opening hours of the venues.
+type AvailabilityConfig struct {
+	Venues map[string]ScheduleConfig `yaml:"venues"` // Opening hours by venue slug, used instead of those of the venue data.
+}
+
+// ScheduleConfig represents the weekly opening hours of a venue.
+type ScheduleConfig struct {
+	Timezone     string              `yaml:"timezone"`      // IANA time zone of the opening hours, e.g. "Europe/Helsinki".
+	OpeningHours map[string][]string `yaml:"opening_hours"` // "HH:MM-HH:MM" intervals by lower case weekday; missing weekdays are closed.
+}
+
+// ExclusionsConfig represents the areas that are never delivered to.
+type ExclusionsConfig struct {
+	File  string            `yaml:"file"`  // GeoJSON FeatureCollection of excluded areas, each feature with an id and a reason.
+	Areas []ExclusionConfig `yaml:"areas"` // Excluded areas defined inline.
+}
+
+// ExclusionConfig represents an area that is never delivered to, by every venue or by one venue.
+type ExclusionConfig struct {
+	ID        string         `yaml:"id"`         // Unique identifier of the area.
+	VenueSlug string         `yaml:"venue_slug"` // Venue the exclusion applies to; empty for every venue.
+	Reason    string         `yaml:"reason"`     // Reason code reported when an address is rejected, e.g. "airport".
+	Geometry  map[string]any `yaml:"geometry"`   // GeoJSON Polygon or MultiPolygon geometry.
+}
+
+// DeliveryZonesConfig represents the polygon delivery zones of the venues.
+type DeliveryZonesConfig struct {
+	File   string                  `yaml:"file"`   // GeoJSON FeatureCollection of zones, each feature naming its venue_slug.
+	Venues map[string][]ZoneConfig `yaml:"venues"` // Zones defined inline by venue slug, checked after those of the file.
+}
+
+// ZoneConfig represents a delivery zone with its own fee components.
+// The fee of an order in the zone is base_price + a + b * distance / 10.
+type ZoneConfig struct {
+	Name     string         `yaml:"name"`     // Name of the zone, reported in the price response.
+	A        int            `yaml:"a"`        // Constant factor for the delivery fee.
+	B        float64        `yaml:"b"`        // Multiplier factor for the delivery fee.
+	Geometry map[string]any `yaml:"geometry"` // GeoJSON Polygon or MultiPolygon geometry.
+}
+
+// VenueConfig represents a venue defined inline in the configuration.
+type VenueConfig struct {
+	Coordinates             []float64       `yaml:"coordinates"`                // Location of the venue [longitude, latitude].
+	OrderMinimumNoSurcharge int             `yaml:"order_minimum_no_surcharge"` // Minimum order value to avoid surcharge.
+	BasePrice               int             `yaml:"base_price"`                 // Base delivery price.
+	DistanceRanges          []DistanceRange `yaml:"distance_ranges"`            // Distance ranges for pricing.
+}
+
+// RateLimitConfig represents a token bucket limit.
+type RateLimitConfig struct {
+	Rate  float64 `yaml:"rate"`  // Requests allowed per second on average.
+	Burst int     `yaml:"burst"` // Maximum number of requests allowed at once.
+}
+
+// PrefetchConfig represents the background refresh of the most requested venues.
+type PrefetchConfig struct {
+	Enabled       bool          `yaml:"enabled"`        // Whether popular venues are refreshed in the background.
+	Interval      time.Duration `yaml:"interval"`       // Time between two refresh cycles.
+	TopK          int           `yaml:"top_k"`          // Number of most requested venues kept warm.
+	TrackedVenues int           `yaml:"tracked_venues"` // Number of venues whose request counts are tracked.
+	LeadTime      time.Duration `yaml:"lead_time"`      // Refresh cached dynamic data expiring within this window.
+	Concurrency   int           `yaml:"concurrency"`    // Maximum number of refreshes running at once.
+}
+
+// CourierModeConfig represents the fee table of a courier transport mode. The fee of a mode
+// follows the venue's base price and the distance range formula: base_price + a + b * distance / 10.
+type CourierModeConfig struct {
+	Name           string          `yaml:"name"`            // Name of the mode, e.g. "bike".
+	DistanceRanges []DistanceRange `yaml:"distance_ranges"` // Distances served by the mode and their fees; max 0 ends the table.
+}
+
+// ETAConfig represents the settings of the delivery time estimates.
+type ETAConfig struct {
+	Enabled               bool                     `yaml:"enabled"`                 // Whether delivery times are estimated.
+	PreparationTime       time.Duration            `yaml:"preparation_time"`        // Default time a venue needs to prepare an order.
+	VenuePreparationTimes map[string]time.Duration `yaml:"venue_preparation_times"` // Preparation time by venue slug.
+	QueueTimes            map[string]time.Duration `yaml:"queue_times"`             // Static extra waiting time by venue slug.
+	Spread                float64                  `yaml:"spread"`                  // Relative width of the min/max bounds.
+	Profiles              []CourierProfileConfig   `yaml:"profiles"`                // Courier speed profiles, in order of preference.
+}
+
+// CourierProfileConfig represents the speed of a kind of courier.
+type CourierProfileConfig struct {
+	Name        string  `yaml:"name"`         // Name of the profile, e.g. "bike".
+	SpeedKMH    float64 `yaml:"speed_kmh"`    // Average speed in km/h, including stops.
+	MaxDistance int     `yaml:"max_distance"` // Longest distance in meters the profile covers; 0 for any distance.
+}
+
+// TracingConfig represents the OpenTelemetry tracing settings.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`      // Whether spans are exported.
+	Exporter    string  `yaml:"exporter"`     // Span exporter: "otlp", "stdout" or "none".
+	Endpoint    string  `yaml:"endpoint"`     // Collector host:port for the OTLP HTTP exporter.
+	Insecure    bool    `yaml:"insecure"`     // Use plain HTTP instead of TLS for the OTLP exporter.
+	ServiceName string  `yaml:"service_name"` // Service name attached to every span.
+	SampleRatio float64 `yaml:"sample_ratio"` // Fraction of new traces to sample, between 0 and 1.
 }
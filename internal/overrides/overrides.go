@@ -0,0 +1,230 @@
+package overrides
+
+import (
+	"backend-wolt-go/internal/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidOverride is returned when an override has no venue slug, overrides nothing or has
+// invalid values.
+var ErrInvalidOverride = errors.New("invalid override")
+
+// maxAuditEntries is the number of most recent changes kept in the audit log.
+const maxAuditEntries = 1000
+
+// Audit log actions.
+const (
+	ActionPut    = "put"
+	ActionDelete = "delete"
+)
+
+// Pricing overrides the delivery pricing of a venue. Unset fields keep the upstream values.
+type Pricing struct {
+	BasePrice      *int                   `json:"base_price,omitempty"`
+	DistanceRanges []models.DistanceRange `json:"distance_ranges,omitempty"`
+}
+
+// Override replaces parts of the dynamic data of a venue, such as during a launch promotion.
+type Override struct {
+	VenueSlug               string     `json:"venue_slug"`
+	DeliveryPricing         *Pricing   `json:"delivery_pricing,omitempty"`
+	OrderMinimumNoSurcharge *int       `json:"order_minimum_no_surcharge,omitempty"`
+	StartsAt                *time.Time `json:"starts_at,omitempty"` // Unset for an override active from its creation.
+	EndsAt                  *time.Time `json:"ends_at,omitempty"`   // Unset for an override active until deleted.
+}
+
+// ActiveAt reports whether the override applies at t, from its start inclusive to its end
+// exclusive.
+func (o Override) ActiveAt(t time.Time) bool {
+	if o.StartsAt != nil && t.Before(*o.StartsAt) {
+		return false
+	}
+	return o.EndsAt == nil || t.Before(*o.EndsAt)
+}
+
+// validate checks the values of the override.
+func (o Override) validate() error {
+	if o.VenueSlug == "" {
+		return fmt.Errorf("%w: missing venue_slug", ErrInvalidOverride)
+	}
+	if o.DeliveryPricing == nil && o.OrderMinimumNoSurcharge == nil {
+		return fmt.Errorf("%w: nothing to override", ErrInvalidOverride)
+	}
+	if o.OrderMinimumNoSurcharge != nil && *o.OrderMinimumNoSurcharge < 0 {
+		return fmt.Errorf("%w: order_minimum_no_surcharge must not be negative", ErrInvalidOverride)
+	}
+	if pricing := o.DeliveryPricing; pricing != nil {
+		if pricing.BasePrice == nil && len(pricing.DistanceRanges) == 0 {
+			return fmt.Errorf("%w: delivery_pricing overrides nothing", ErrInvalidOverride)
+		}
+		if pricing.BasePrice != nil && *pricing.BasePrice < 0 {
+			return fmt.Errorf("%w: base_price must not be negative", ErrInvalidOverride)
+		}
+		if err := validateDistanceRanges(pricing.DistanceRanges); err != nil {
+			return err
+		}
+	}
+	if o.StartsAt != nil && o.EndsAt != nil && !o.EndsAt.After(*o.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidOverride)
+	}
+	return nil
+}
+
+// validateDistanceRanges checks that the fees are not negative and that the ranges start at 0 and
+// follow each other without gaps or overlaps, with only the last one open-ended (max 0) so that it
+// marks the distance from which delivery is not possible.
+func validateDistanceRanges(ranges []models.DistanceRange) error {
+	for i, dr := range ranges {
+		if dr.A < 0 || dr.B < 0 {
+			return fmt.Errorf("%w: distance range %d has a negative fee", ErrInvalidOverride, i)
+		}
+		start := 0
+		if i > 0 {
+			start = ranges[i-1].Max
+		}
+		if dr.Min != start {
+			return fmt.Errorf("%w: distance range %d must start at %d", ErrInvalidOverride, i, start)
+		}
+		last := i == len(ranges)-1
+		if last && dr.Max != 0 {
+			return fmt.Errorf("%w: the last distance range must be open-ended (max 0)", ErrInvalidOverride)
+		}
+		if !last && dr.Max <= dr.Min {
+			return fmt.Errorf("%w: distance range %d is empty", ErrInvalidOverride, i)
+		}
+	}
+	return nil
+}
+
+// AuditEntry records a change of the overrides.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"` // ActionPut or ActionDelete.
+	VenueSlug string    `json:"venue_slug"`
+	Actor     string    `json:"actor,omitempty"`    // Authenticated caller making the change, if known.
+	Previous  *Override `json:"previous,omitempty"` // Override replaced or deleted, if any.
+	Override  *Override `json:"override,omitempty"` // Override stored by a put.
+}
+
+// Store holds the pricing overrides by venue slug and the audit log of their changes. It is safe
+// for concurrent use, so that overrides can be updated while orders are priced.
+type Store struct {
+	mu                   sync.RWMutex
+	overrides            map[string]Override
+	audit                []AuditEntry // Most recent changes, oldest first.
+	logger               *slog.Logger // Receives every change, if set.
+	now                  func() time.Time
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithLogger writes every change of the overrides to logger, so that the audit trail outlives
+// the entries kept in memory and restarts.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Store) {
+		s.logger = logger
+	}
+}
+
+// NewStore creates an empty Store with the options.
+func NewStore(opts ...Option) *Store {
+	s := &Store{overrides: make(map[string]Override), now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Put stores the override of its venue, replacing the previous one if any, and records the
+// change made by the actor in the audit log.
+func (s *Store) Put(override Override, actor string) error {
+	if err := override.validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := AuditEntry{Action: ActionPut, VenueSlug: override.VenueSlug, Actor: actor, Override: &override}
+	if previous, ok := s.overrides[override.VenueSlug]; ok {
+		entry.Previous = &previous
+	}
+	s.overrides[override.VenueSlug] = override
+	s.record(entry)
+	return nil
+}
+
+// Delete removes the override of the venue and reports whether it existed. Removals are recorded
+// in the audit log.
+func (s *Store) Delete(venueSlug, actor string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.overrides[venueSlug]
+	if !ok {
+		return false
+	}
+	delete(s.overrides, venueSlug)
+	s.record(AuditEntry{Action: ActionDelete, VenueSlug: venueSlug, Actor: actor, Previous: &previous})
+	return true
+}
+
+// record appends the entry to the audit log, dropping the oldest entries beyond its capacity,
+// and writes it to the logger if set. s.mu must be held, so that changes are logged in order.
+func (s *Store) record(entry AuditEntry) {
+	entry.Time = s.now()
+	s.audit = append(s.audit, entry)
+	if len(s.audit) > maxAuditEntries {
+		s.audit = append([]AuditEntry(nil), s.audit[len(s.audit)-maxAuditEntries:]...)
+	}
+	if s.logger != nil {
+		s.logger.Info("pricing override changed",
+			slog.String("action", entry.Action),
+			slog.String("venue_slug", entry.VenueSlug),
+			slog.String("actor", entry.Actor),
+			slog.Any("previous", entry.Previous),
+			slog.Any("override", entry.Override),
+		)
+	}
+}
+
+// Get returns the override of the venue, whether active or not.
+func (s *Store) Get(venueSlug string) (Override, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	override, ok := s.overrides[venueSlug]
+	return override, ok
+}
+
+// List returns the overrides ordered by venue slug.
+func (s *Store) List() []Override {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Override, 0, len(s.overrides))
+	for _, override := range s.overrides {
+		list = append(list, override)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].VenueSlug < list[j].VenueSlug })
+	return list
+}
+
+// Active returns the override of the venue if it applies at t.
+func (s *Store) Active(venueSlug string, t time.Time) (Override, bool) {
+	override, ok := s.Get(venueSlug)
+	if !ok || !override.ActiveAt(t) {
+		return Override{}, false
+	}
+	return override, true
+}
+
+// Audit returns the recorded changes, oldest first. It is never nil, so that an empty log is
+// encoded as an empty JSON array.
+func (s *Store) Audit() []AuditEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]AuditEntry{}, s.audit...)
+}
@@ -0,0 +1,138 @@
+package overrides
+
+import (
+	"backend-wolt-go/internal/models"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int { return &v }
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestOverride_ActiveAt(t *testing.T) {
+	start := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	override := Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(0), StartsAt: &start, EndsAt: &end}
+
+	assert.False(t, override.ActiveAt(start.Add(-time.Second)))
+	assert.True(t, override.ActiveAt(start))
+	assert.True(t, override.ActiveAt(end.Add(-time.Second)))
+	assert.False(t, override.ActiveAt(end))
+
+	// Without bounds, the override is always active.
+	assert.True(t, Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(0)}.ActiveAt(start))
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	changedAt := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return changedAt }
+
+	start := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	promotion := Override{VenueSlug: "venue", DeliveryPricing: &Pricing{BasePrice: intPtr(0)}, StartsAt: &start}
+	assert.NoError(t, store.Put(promotion, "ops"))
+
+	_, ok := store.Active("venue", start.Add(-time.Minute))
+	assert.False(t, ok)
+	override, ok := store.Active("venue", start)
+	if assert.True(t, ok) {
+		assert.Equal(t, 0, *override.DeliveryPricing.BasePrice)
+	}
+	_, ok = store.Active("other-venue", start)
+	assert.False(t, ok)
+
+	minimum := Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(500)}
+	assert.NoError(t, store.Put(minimum, "ops"))
+	assert.NoError(t, store.Put(Override{VenueSlug: "another-venue", OrderMinimumNoSurcharge: intPtr(0)}, ""))
+	assert.Equal(t, []Override{{VenueSlug: "another-venue", OrderMinimumNoSurcharge: intPtr(0)}, minimum}, store.List())
+
+	assert.True(t, store.Delete("venue", "admin"))
+	assert.False(t, store.Delete("venue", "admin"))
+	_, ok = store.Get("venue")
+	assert.False(t, ok)
+
+	// Every change is recorded with the override it replaced.
+	assert.Equal(t, []AuditEntry{
+		{Time: changedAt, Action: ActionPut, VenueSlug: "venue", Actor: "ops", Override: &promotion},
+		{Time: changedAt, Action: ActionPut, VenueSlug: "venue", Actor: "ops", Previous: &promotion, Override: &minimum},
+		{Time: changedAt, Action: ActionPut, VenueSlug: "another-venue", Override: &Override{VenueSlug: "another-venue", OrderMinimumNoSurcharge: intPtr(0)}},
+		{Time: changedAt, Action: ActionDelete, VenueSlug: "venue", Actor: "admin", Previous: &minimum},
+	}, store.Audit())
+}
+
+func TestStore_AuditCapacity(t *testing.T) {
+	store := NewStore()
+	for i := range maxAuditEntries + 10 {
+		assert.NoError(t, store.Put(Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(i)}, ""))
+	}
+
+	audit := store.Audit()
+	assert.Len(t, audit, maxAuditEntries)
+	assert.Equal(t, 10, *audit[0].Override.OrderMinimumNoSurcharge)
+}
+
+func TestStore_PutInvalid(t *testing.T) {
+	start := time.Date(2030, time.January, 7, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		override Override
+	}{
+		{"MissingVenueSlug", Override{OrderMinimumNoSurcharge: intPtr(0)}},
+		{"NothingOverridden", Override{VenueSlug: "venue"}},
+		{"EmptyPricing", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{}}},
+		{"NegativeBasePrice", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{BasePrice: intPtr(-1)}}},
+		{"NegativeOrderMinimum", Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(-1)}},
+		{"EmptyDistanceRange", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 500}, {Min: 500, Max: 500}, {Min: 500, Max: 0}}}}},
+		{"NegativeConstantFee", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 500, A: -100}, {Min: 500, Max: 0}}}}},
+		{"NegativeDistanceFee", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 500, B: -1}, {Min: 500, Max: 0}}}}},
+		{"FirstRangeNotFromZero", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 100, Max: 500}, {Min: 500, Max: 0}}}}},
+		{"GapBetweenRanges", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 500}, {Min: 600, Max: 0}}}}},
+		{"OverlappingRanges", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 500}, {Min: 400, Max: 0}}}}},
+		{"UnorderedRanges", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 500, Max: 1000}, {Min: 0, Max: 500}, {Min: 1000, Max: 0}}}}},
+		{"OpenEndedRangeNotLast", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 0}, {Min: 500, Max: 0}}}}},
+		{"LastRangeNotOpenEnded", Override{VenueSlug: "venue", DeliveryPricing: &Pricing{DistanceRanges: []models.DistanceRange{{Min: 0, Max: 500}}}}},
+		{"EndsBeforeStart", Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(0), StartsAt: &start, EndsAt: timePtr(start.Add(-time.Hour))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			assert.ErrorIs(t, store.Put(tt.override, ""), ErrInvalidOverride)
+			assert.Empty(t, store.Audit())
+		})
+	}
+}
+
+func TestStore_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	store := NewStore(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	assert.NoError(t, store.Put(Override{VenueSlug: "venue", OrderMinimumNoSurcharge: intPtr(0)}, "key:ops"))
+	assert.True(t, store.Delete("venue", "key:ops"))
+
+	// Every change is logged with the override before and after it.
+	var lines []map[string]any
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var line map[string]any
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatalf("invalid log line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, "pricing override changed", lines[0]["msg"])
+		assert.Equal(t, ActionPut, lines[0]["action"])
+		assert.Equal(t, "key:ops", lines[0]["actor"])
+		assert.Nil(t, lines[0]["previous"])
+		assert.Equal(t, "venue", lines[0]["override"].(map[string]any)["venue_slug"])
+		assert.Equal(t, ActionDelete, lines[1]["action"])
+		assert.Equal(t, "venue", lines[1]["previous"].(map[string]any)["venue_slug"])
+		assert.Nil(t, lines[1]["override"])
+	}
+}
@@ -0,0 +1,270 @@
+package quotelog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rotatedSuffix is the time layout appended to the names of rotated files. It sorts
+// chronologically.
+const rotatedSuffix = "20060102T150405.000000000"
+
+// FileSink writes the events as JSON Lines to a file, rotated once it reaches its maximum size.
+// Rotated files are renamed with the time of the rotation appended, and the oldest ones are
+// removed beyond the maximum number of backups. It is safe for concurrent use.
+type FileSink struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64 // Size of the current file.
+	now        func() time.Time
+}
+
+// NewFileSink opens the file at path for appending, creating it and its directory if needed.
+// The file is rotated before exceeding maxSize bytes, 100 MB if 0, and maxBackups rotated files
+// are kept, all of them if 0.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	if maxSize <= 0 {
+		maxSize = 100 << 20
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("could not create quote log directory: %w", err)
+	}
+	s := &FileSink{path: path, maxSize: maxSize, maxBackups: maxBackups, now: time.Now}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the current file for appending. A last line cut short, such as by a crash, is
+// terminated so that the next event starts on its own line.
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open quote log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("could not open quote log: %w", err)
+	}
+	size := info.Size()
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, size-1); err != nil {
+			file.Close()
+			return fmt.Errorf("could not read quote log: %w", err)
+		}
+		if last[0] != '\n' {
+			n, err := file.Write([]byte{'\n'})
+			size += int64(n)
+			if err != nil {
+				file.Close()
+				return fmt.Errorf("could not write quote log: %w", err)
+			}
+		}
+	}
+	s.file, s.size = file, size
+	return nil
+}
+
+// Write appends the event to the current file, rotating it first if the event would not fit.
+// The event is still written when removing old rotated files fails, and that error is returned.
+func (s *FileSink) Write(_ context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("could not encode quote event: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("quote log is closed")
+	}
+	var rotateErr error
+	if s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		rotateErr = s.rotate()
+		if s.file == nil {
+			return rotateErr
+		}
+	}
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("could not write quote event: %w", err)
+	}
+	return rotateErr
+}
+
+// rotate renames the current file, removes the oldest rotated files beyond the maximum number
+// of backups and opens a new file. The current file is reopened whatever fails, so that the log
+// stays open unless the file itself cannot be opened. s.mu must be held.
+func (s *FileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		err = fmt.Errorf("could not close quote log: %w", err)
+	} else if err = os.Rename(s.path, s.path+"."+s.now().UTC().Format(rotatedSuffix)); err != nil {
+		// Keep appending to the current file rather than losing events.
+		err = fmt.Errorf("could not rotate quote log: %w", err)
+	} else {
+		err = s.removeBackups()
+	}
+	if openErr := s.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+// removeBackups removes the oldest rotated files beyond the maximum number of backups.
+func (s *FileSink) removeBackups() error {
+	if s.maxBackups <= 0 {
+		return nil
+	}
+	rotated, err := s.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	for len(rotated) > s.maxBackups {
+		if err := os.Remove(rotated[0]); err != nil {
+			return fmt.Errorf("could not remove rotated quote log: %w", err)
+		}
+		rotated = rotated[1:]
+	}
+	return nil
+}
+
+// rotatedFiles returns the paths of the rotated files, oldest first.
+func (s *FileSink) rotatedFiles() ([]string, error) {
+	rotated, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("could not list rotated quote logs: %w", err)
+	}
+	sort.Strings(rotated)
+	return rotated, nil
+}
+
+// Find returns the events of the current and rotated files whose quote ID or request ID is id,
+// oldest first. The files are opened while holding the lock and scanned after releasing it, up to
+// their size at that time, so that a lookup does not block writes while it scans every file.
+func (s *FileSink) Find(ctx context.Context, id string) ([]Event, error) {
+	sections, err := s.openSections()
+	if err != nil {
+		return nil, err
+	}
+	defer closeSections(sections)
+
+	var events []Event
+	for _, section := range sections {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		found, err := findInReader(io.NewSectionReader(section.file, 0, section.size), id)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, found...)
+	}
+	return events, nil
+}
+
+// fileSection is an open quote log file and the size of it written when it was opened.
+type fileSection struct {
+	file *os.File
+	size int64
+}
+
+// openSections opens the rotated files, oldest first, and the current file. Open files are still
+// read after they are rotated or removed.
+func (s *FileSink) openSections() ([]fileSection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := s.rotatedFiles()
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, s.path)
+
+	sections := make([]fileSection, 0, len(paths))
+	for _, path := range paths {
+		section, err := openSection(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // Removed since it was listed.
+		}
+		if err != nil {
+			closeSections(sections)
+			return nil, fmt.Errorf("could not open quote log: %w", err)
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// openSection opens the file at path for reading up to its current size.
+func openSection(path string) (fileSection, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return fileSection{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fileSection{}, err
+	}
+	return fileSection{file: file, size: info.Size()}, nil
+}
+
+// closeSections closes the files of the sections.
+func closeSections(sections []fileSection) {
+	for _, section := range sections {
+		section.file.Close()
+	}
+}
+
+// findInReader returns the events read from r whose quote ID or request ID is id. Lines that are
+// not valid events, such as one cut short by a crash, are skipped.
+func findInReader(r io.Reader, id string) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if event.QuoteID == id || (event.RequestID != "" && event.RequestID == id) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read quote log: %w", err)
+	}
+	return events, nil
+}
+
+// Close closes the current file. Later writes fail.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
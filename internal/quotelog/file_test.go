@@ -0,0 +1,134 @@
+package quotelog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSink_Find(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes", "quotes.jsonl")
+	sink, err := NewFileSink(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+
+	at := time.Date(2030, time.January, 7, 12, 0, 0, 0, time.UTC)
+	first := Event{QuoteID: "quote-1", RequestID: "request-1", Time: at, Error: "delivery is not possible, distance too long"}
+	second := Event{QuoteID: "quote-2", RequestID: "request-1", Time: at, Distance: 177}
+	other := Event{QuoteID: "quote-3", Time: at}
+	for _, event := range []Event{first, second, other} {
+		assert.NoError(t, sink.Write(context.Background(), event))
+	}
+
+	events, err := sink.Find(context.Background(), "quote-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{second}, events)
+
+	// A request ID finds every calculation of the request.
+	events, err = sink.Find(context.Background(), "request-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{first, second}, events)
+
+	events, err = sink.Find(context.Background(), "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.jsonl")
+	// Each event is larger than the maximum size, so every file holds a single event.
+	sink, err := NewFileSink(path, 150, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+	rotations := 0
+	sink.now = func() time.Time {
+		rotations++
+		return time.Date(2030, time.January, 7, 12, 0, rotations, 0, time.UTC)
+	}
+
+	ids := []string{"quote-1", "quote-2", "quote-3", "quote-4"}
+	for _, id := range ids {
+		assert.NoError(t, sink.Write(context.Background(), Event{QuoteID: id}))
+	}
+
+	rotated, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{path + ".20300107T120002.000000000", path + ".20300107T120003.000000000"}, rotated)
+
+	// Events of the removed files are gone, those of the kept ones are still found.
+	events, err := sink.Find(context.Background(), "quote-1")
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	for _, id := range ids[1:] {
+		events, err := sink.Find(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1, id)
+	}
+}
+
+func TestFileSink_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.jsonl")
+	sink, err := NewFileSink(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	assert.NoError(t, sink.Write(context.Background(), Event{QuoteID: "quote-1"}))
+	assert.NoError(t, sink.Close())
+	assert.Error(t, sink.Write(context.Background(), Event{QuoteID: "quote-2"}))
+
+	// A line cut short by a crash is skipped, and later events are appended after it.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("failed to open quote log: %v", err)
+	}
+	file.WriteString(`{"quote_id": "quote-`)
+	file.Close()
+
+	sink, err = NewFileSink(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+	assert.NoError(t, sink.Write(context.Background(), Event{QuoteID: "quote-3"}))
+
+	events, err := sink.Find(context.Background(), "quote-1")
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	events, err = sink.Find(context.Background(), "quote-3")
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestFileSink_RotationCleanupFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.jsonl")
+	sink, err := NewFileSink(path, 150, 1)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+	// The oldest rotated file is a non-empty directory, which cannot be removed.
+	if err := os.MkdirAll(filepath.Join(path+".00000000T000000.000000000", "stuck"), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	assert.NoError(t, sink.Write(context.Background(), Event{QuoteID: "quote-1"}))
+	assert.Error(t, sink.Write(context.Background(), Event{QuoteID: "quote-2"}))
+
+	// The events are written despite the failed cleanup, and the log stays open.
+	assert.Error(t, sink.Write(context.Background(), Event{QuoteID: "quote-3"}))
+	assert.NoError(t, os.RemoveAll(path+".00000000T000000.000000000"))
+	assert.NoError(t, sink.Write(context.Background(), Event{QuoteID: "quote-4"}))
+	for _, id := range []string{"quote-3", "quote-4"} {
+		events, err := sink.Find(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1, id)
+	}
+}
@@ -0,0 +1,95 @@
+package quotelog
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Event is the append-only record of a price calculation, successful or not, holding what is
+// needed to reconstruct the price: the order, the venue data it was computed from, the matched
+// distance range and the result or error.
+type Event struct {
+	QuoteID   string           `json:"quote_id"`
+	RequestID string           `json:"request_id,omitempty"` // ID of the HTTP request, if any.
+	Time      time.Time        `json:"time"`
+	Order     models.OrderInfo `json:"order"` // Order with the user coordinates rounded, see NewEvent.
+
+	VenueVersion   string        `json:"venue_version,omitempty"`    // Hash of the venue data, empty if it was not fetched.
+	VenueFetchedAt *time.Time    `json:"venue_fetched_at,omitempty"` // Time the venue data was fetched from the venue API, if known.
+	Overridden     bool          `json:"overridden,omitempty"`       // Set when a pricing override applied.
+	Distance       int           `json:"distance,omitempty"`         // Distance in meters, once computed.
+	Range          *MatchedRange `json:"range,omitempty"`            // Distance range used for the fee, if any.
+
+	Result *models.PriceResponse `json:"result,omitempty"` // Price returned, for successful calculations.
+	Error  string                `json:"error,omitempty"`  // Error returned, for failed calculations.
+}
+
+// MatchedRange is the distance range the fee was computed with and its position in the list:
+// a range of the venue, or of the fee table of a courier mode. The fee components of a delivery
+// zone are recorded as a single unbounded range.
+type MatchedRange struct {
+	Index int    `json:"index"`
+	Mode  string `json:"mode,omitempty"` // Courier mode whose fee table holds the range, if any.
+	Zone  string `json:"zone,omitempty"` // Delivery zone whose fee components are used, if any.
+	models.DistanceRange
+}
+
+// coordinatePrecision is the number of decimals the user coordinates are rounded to in the events,
+// about 100 meters: enough to tell the area and delivery zone of an order without storing the
+// address of the user. The price itself only depends on the recorded distance.
+const coordinatePrecision = 3
+
+// NewEvent starts the event of a calculation for the order at t, with a new quote ID and the ID
+// of the request carried by ctx. The user coordinates are rounded to coordinatePrecision.
+func NewEvent(ctx context.Context, orderInfo *models.OrderInfo, t time.Time) *Event {
+	order := *orderInfo
+	order.Lat = roundCoordinate(order.Lat)
+	order.Lon = roundCoordinate(order.Lon)
+	return &Event{QuoteID: newID(), RequestID: middleware.GetReqID(ctx), Time: t, Order: order}
+}
+
+// roundCoordinate rounds a latitude or longitude to coordinatePrecision decimals.
+func roundCoordinate(degrees float64) float64 {
+	scale := math.Pow10(coordinatePrecision)
+	return math.Round(degrees*scale) / scale
+}
+
+// newID returns a random 128-bit identifier in hexadecimal.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // Never fails, see crypto/rand.Read.
+	return hex.EncodeToString(b[:])
+}
+
+// VenueVersion returns a hash identifying the content of the venue data, so that quotes computed
+// from the same data can be told apart from those computed after it changed.
+func VenueVersion(staticResponse *models.VenueStaticResponse, dynamicResponse *models.VenueDynamicResponse) string {
+	hash := sha256.New()
+	for _, response := range []any{staticResponse, dynamicResponse} {
+		data, err := json.Marshal(response)
+		if err != nil {
+			return ""
+		}
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Sink receives the quote events.
+type Sink interface {
+	Write(ctx context.Context, event Event) error
+}
+
+// Finder looks up quote events.
+type Finder interface {
+	// Find returns the events whose quote ID or request ID is id, oldest first.
+	Find(ctx context.Context, id string) ([]Event, error)
+}
@@ -0,0 +1,51 @@
+package quotelog
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEvent(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "request-1")
+	at := time.Date(2030, time.January, 7, 12, 0, 0, 0, time.UTC)
+	orderInfo := &models.OrderInfo{Slug: "venue", Lat: 60.17094, Lon: 24.93087, CartValue: 1000}
+
+	event := NewEvent(ctx, orderInfo, at)
+	assert.Len(t, event.QuoteID, 32)
+	assert.Equal(t, "request-1", event.RequestID)
+	assert.Equal(t, at, event.Time)
+	// The user coordinates are only recorded to about 100 meters.
+	assert.Equal(t, models.OrderInfo{Slug: "venue", Lat: 60.171, Lon: 24.931, CartValue: 1000}, event.Order)
+	assert.Equal(t, 60.17094, orderInfo.Lat)
+
+	assert.NotEqual(t, event.QuoteID, NewEvent(ctx, orderInfo, at).QuoteID)
+}
+
+func TestVenueVersion(t *testing.T) {
+	decode := func(dynamicJSON string) (*models.VenueStaticResponse, *models.VenueDynamicResponse) {
+		var staticResponse models.VenueStaticResponse
+		var dynamicResponse models.VenueDynamicResponse
+		if err := json.Unmarshal([]byte(`{"venue_raw": {"location": {"coordinates": [24.93, 60.17]}}}`), &staticResponse); err != nil {
+			t.Fatalf("invalid static data: %v", err)
+		}
+		if err := json.Unmarshal([]byte(dynamicJSON), &dynamicResponse); err != nil {
+			t.Fatalf("invalid dynamic data: %v", err)
+		}
+		return &staticResponse, &dynamicResponse
+	}
+
+	version := VenueVersion(decode(`{"venue_raw": {"delivery_specs": {"delivery_pricing": {"base_price": 190}}}}`))
+	assert.Len(t, version, 64)
+
+	// The version depends on the content of the data only.
+	staticResponse, dynamicResponse := decode(`{"venue_raw": {"delivery_specs": {"delivery_pricing": {"base_price": 190}}}}`)
+	dynamicResponse.FetchedAt = time.Now()
+	assert.Equal(t, version, VenueVersion(staticResponse, dynamicResponse))
+	assert.NotEqual(t, version, VenueVersion(decode(`{"venue_raw": {"delivery_specs": {"delivery_pricing": {"base_price": 290}}}}`)))
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"backend-wolt-go/internal/auth"
+	"backend-wolt-go/internal/logging"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Limiter applies a single Limit to buckets within a key namespace.
+type Limiter struct {
+	store  Store
+	limit  Limit
+	prefix string
+}
+
+// NewLimiter creates a Limiter storing its buckets in store under keys starting with prefix.
+func NewLimiter(store Store, limit Limit, prefix string) *Limiter {
+	return &Limiter{store: store, limit: limit, prefix: prefix}
+}
+
+// Allow takes a token for key. It returns a *LimitExceededError when the bucket is empty.
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	key = l.prefix + key
+	result, err := l.store.Allow(ctx, key, l.limit)
+	if err != nil {
+		return Result{}, err
+	}
+	if !result.Allowed {
+		return result, &LimitExceededError{Key: key, RetryAfter: result.RetryAfter}
+	}
+	return result, nil
+}
+
+// KeyFunc extracts the client identity used to select a bucket.
+type KeyFunc func(r *http.Request) string
+
+// IdentityOrIP identifies clients by their authenticated identity: the ID of their API key or
+// the subject of their bearer token. Unauthenticated clients are identified by their IP, since
+// unverified headers can be changed on every request to get a new bucket.
+func IdentityOrIP(r *http.Request) string {
+	if identity, ok := auth.IdentityFromContext(r.Context()); ok {
+		switch {
+		case identity.KeyID != "":
+			return "key:" + identity.KeyID
+		case identity.Subject != "":
+			return "sub:" + identity.Subject
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// Middleware rejects requests with 429 Too Many Requests once the client's bucket is empty.
+// Every response carries the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers.
+// If the store fails the request is let through, so that an outage of a shared store does not
+// take the service down with it.
+func Middleware(limiter *Limiter, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.Allow(r.Context(), keyFunc(r))
+			var limitErr *LimitExceededError
+			if err != nil && !errors.As(err, &limitErr) {
+				logging.FromContext(r.Context()).ErrorContext(r.Context(), "rate limit store failed", slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			SetHeaders(w, result)
+			if !result.Allowed {
+				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SetHeaders writes the rate limit headers for the result, including Retry-After for rejected requests.
+func SetHeaders(w http.ResponseWriter, result Result) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	if !result.Allowed {
+		SetRetryAfter(w, result.RetryAfter)
+	}
+}
+
+// SetRetryAfter writes the Retry-After header in whole seconds, rounded up.
+func SetRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+}
+
+// ceilSeconds rounds a duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
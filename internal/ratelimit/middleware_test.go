@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"backend-wolt-go/internal/auth"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore is a Store that always fails.
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	store, _ := newTestStore()
+	handler := Middleware(NewLimiter(store, Limit{Rate: 0.5, Burst: 1}, "client:"), IdentityOrIP)(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "key-1"}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Reset"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// A different API key is not affected.
+	other := httptest.NewRequest(http.MethodGet, "/", nil)
+	other = other.WithContext(auth.WithIdentity(other.Context(), &auth.Identity{KeyID: "key-2"}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, other)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestMiddleware_StoreFailureFailsOpen(t *testing.T) {
+	handler := Middleware(NewLimiter(failingStore{}, Limit{Rate: 1, Burst: 1}, ""), IdentityOrIP)(okHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIdentityOrIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	assert.Equal(t, "ip:192.0.2.1", IdentityOrIP(req))
+
+	// Unverified headers do not select the bucket.
+	req.Header.Set("X-API-Key", "random")
+	assert.Equal(t, "ip:192.0.2.1", IdentityOrIP(req))
+
+	keyReq := req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{KeyID: "key-1"}))
+	assert.Equal(t, "key:key-1", IdentityOrIP(keyReq))
+	tokenReq := req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{Subject: "user-1"}))
+	assert.Equal(t, "sub:user-1", IdentityOrIP(tokenReq))
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit describes a token bucket: Rate tokens are added per second, up to Burst tokens.
+type Limit struct {
+	Rate  float64 // Tokens added per second.
+	Burst int     // Maximum number of tokens in the bucket.
+}
+
+// Result is the outcome of a single Allow call.
+type Result struct {
+	Allowed    bool          // Whether the request may proceed.
+	Limit      int           // Bucket capacity, reported in the X-RateLimit-Limit header.
+	Remaining  int           // Whole tokens left after this request.
+	RetryAfter time.Duration // Time until the next token is available when the request was rejected.
+	ResetAfter time.Duration // Time until the bucket is full again.
+}
+
+// Store keeps the token buckets. The in-memory implementation is used by default; a shared store
+// (for example Redis) can implement the same interface to enforce limits across replicas.
+type Store interface {
+	// Allow takes one token from the bucket identified by key.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// LimitExceededError is returned when a request is rejected by a limiter.
+type LimitExceededError struct {
+	Key        string        // Bucket that ran out of tokens.
+	RetryAfter time.Duration // Time until the next token is available.
+}
+
+// Error implements the error interface.
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s, retry after %s", e.Key, e.RetryAfter)
+}
+
+// bucket is the state of a single token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // Time at which the bucket is full again and may be evicted.
+}
+
+// MemoryStore is a Store keeping the buckets in process memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// sweepInterval is how often idle buckets are evicted from a MemoryStore.
+const sweepInterval = time.Minute
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes one token from the bucket identified by key, creating a full bucket on first use.
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return Result{}, fmt.Errorf("invalid limit: rate %v, burst %d", limit.Rate, limit.Burst)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	// Refill the bucket for the time elapsed since the last request.
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.last = now
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+	b.full = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweep evicts the buckets that have refilled completely, since they are equivalent to new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// secondsToDuration converts fractional seconds to a duration.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
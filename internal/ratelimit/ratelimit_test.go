@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock for deterministic bucket refills.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStore_Allow(t *testing.T) {
+	store, clock := newTestStore()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	// The bucket starts full.
+	for i, wantRemaining := range []int{1, 0} {
+		result, err := store.Allow(context.Background(), "a", limit)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d should be allowed", i)
+		assert.Equal(t, wantRemaining, result.Remaining)
+		assert.Equal(t, 2, result.Limit)
+	}
+
+	// The third request is rejected until a token is refilled.
+	result, err := store.Allow(context.Background(), "a", limit)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.ResetAfter)
+
+	// Other keys have their own bucket.
+	result, _ = store.Allow(context.Background(), "b", limit)
+	assert.True(t, result.Allowed)
+
+	clock.now = clock.now.Add(time.Second)
+	result, _ = store.Allow(context.Background(), "a", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryStore_InvalidLimit(t *testing.T) {
+	store, _ := newTestStore()
+	_, err := store.Allow(context.Background(), "a", Limit{Rate: 0, Burst: 1})
+	assert.Error(t, err)
+}
+
+func TestMemoryStore_EvictsFullBuckets(t *testing.T) {
+	store, clock := newTestStore()
+	limit := Limit{Rate: 1, Burst: 1}
+
+	_, _ = store.Allow(context.Background(), "idle", limit)
+	clock.now = clock.now.Add(2 * sweepInterval)
+	_, _ = store.Allow(context.Background(), "active", limit)
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "active")
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	store, _ := newTestStore()
+	limiter := NewLimiter(store, Limit{Rate: 1, Burst: 1}, "venue:")
+
+	_, err := limiter.Allow(context.Background(), "abc")
+	assert.NoError(t, err)
+
+	_, err = limiter.Allow(context.Background(), "abc")
+	var limitErr *LimitExceededError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, "venue:abc", limitErr.Key)
+		assert.Equal(t, time.Second, limitErr.RetryAfter)
+	}
+}
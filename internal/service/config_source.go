@@ -0,0 +1,78 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ConfigVenueSource serves venues defined inline in the configuration.
+type ConfigVenueSource struct {
+	venues map[string]configVenue
+}
+
+// configVenue holds the venue API responses built from a configured venue.
+type configVenue struct {
+	static  []byte
+	dynamic []byte
+}
+
+// NewConfigVenueSource creates a ConfigVenueSource serving the given venues by slug.
+func NewConfigVenueSource(venues map[string]models.VenueConfig) (*ConfigVenueSource, error) {
+	s := &ConfigVenueSource{venues: make(map[string]configVenue, len(venues))}
+	for venueSlug, venue := range venues {
+		if len(venue.Coordinates) != 2 {
+			return nil, fmt.Errorf("venue %s: coordinates must be [longitude, latitude]", venueSlug)
+		}
+
+		// Keep the venue in the venue API format so that every lookup decodes a fresh copy,
+		// which callers are free to modify.
+		distanceRanges := venue.DistanceRanges
+		if distanceRanges == nil {
+			distanceRanges = []models.DistanceRange{}
+		}
+		static, err := json.Marshal(map[string]any{
+			"venue_raw": map[string]any{
+				"location": map[string]any{"coordinates": venue.Coordinates},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("venue %s: %w", venueSlug, err)
+		}
+		dynamic, err := json.Marshal(map[string]any{
+			"venue_raw": map[string]any{
+				"delivery_specs": map[string]any{
+					"order_minimum_no_surcharge": venue.OrderMinimumNoSurcharge,
+					"delivery_pricing": map[string]any{
+						"base_price":      venue.BasePrice,
+						"distance_ranges": distanceRanges,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("venue %s: %w", venueSlug, err)
+		}
+		s.venues[venueSlug] = configVenue{static: static, dynamic: dynamic}
+	}
+	return s, nil
+}
+
+// GetVenueInformation returns the configured venue.
+func (s *ConfigVenueSource) GetVenueInformation(_ context.Context, venueSlug string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	venue, ok := s.venues[venueSlug]
+	if !ok {
+		return nil, nil, fmt.Errorf("venue %s: %w", venueSlug, ErrVenueNotFound)
+	}
+
+	staticData := &models.VenueStaticResponse{}
+	if err := json.Unmarshal(venue.static, staticData); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode venue %s: %w", venueSlug, err)
+	}
+	dynamicData := &models.VenueDynamicResponse{}
+	if err := json.Unmarshal(venue.dynamic, dynamicData); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode venue %s: %w", venueSlug, err)
+	}
+	return staticData, dynamicData, nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigVenueSource(t *testing.T) {
+	source, err := NewConfigVenueSource(map[string]models.VenueConfig{
+		"venue": {
+			Coordinates:             []float64{24.9354, 60.1699},
+			OrderMinimumNoSurcharge: 1000,
+			BasePrice:               190,
+			DistanceRanges:          []models.DistanceRange{{Min: 0, Max: 500}, {Min: 500, Max: 0}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	staticData, dynamicData, err := source.GetVenueInformation(context.Background(), "venue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []float64{24.9354, 60.1699}, staticData.VenueRaw.Location.Coordinates)
+	specs := dynamicData.VenueRaw.DeliverySpecs
+	assert.Equal(t, 1000, specs.OrderMinimumNoSurcharge)
+	assert.Equal(t, 190, specs.DeliveryPricing.BasePrice)
+	assert.Len(t, specs.DeliveryPricing.DistanceRanges, 2)
+
+	// Every lookup returns its own copy.
+	specs.DeliveryPricing.BasePrice = 0
+	_, dynamicData, err = source.GetVenueInformation(context.Background(), "venue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, 190, dynamicData.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice)
+
+	_, _, err = source.GetVenueInformation(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrVenueNotFound)
+}
+
+func TestNewConfigVenueSource_InvalidCoordinates(t *testing.T) {
+	_, err := NewConfigVenueSource(map[string]models.VenueConfig{"venue": {Coordinates: []float64{24.9354}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrVenueNotFound is returned by the venue sources for slugs they do not know, including when
+// the venue API answers with 404 Not Found.
+var ErrVenueNotFound = errors.New("venue not found")
+
+// FileVenueSource serves venue information from a directory of JSON fixtures laid out as
+// <slug>/static.json and <slug>/dynamic.json, in the format of the venue API responses.
+// The files are read on every lookup, so fixtures can be edited while the server runs.
+type FileVenueSource struct {
+	dir string
+}
+
+// NewFileVenueSource creates a FileVenueSource reading the fixtures in dir.
+func NewFileVenueSource(dir string) (*FileVenueSource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not open fixture directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+	return &FileVenueSource{dir: dir}, nil
+}
+
+// GetVenueInformation reads the static and dynamic fixtures of the venue.
+func (s *FileVenueSource) GetVenueInformation(_ context.Context, venueSlug string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	// Reject slugs that could point outside of the fixture directory.
+	if venueSlug == "" || venueSlug == "." || venueSlug == ".." || strings.ContainsAny(venueSlug, `/\`) {
+		return nil, nil, fmt.Errorf("venue %s: %w", venueSlug, ErrVenueNotFound)
+	}
+
+	staticData := &models.VenueStaticResponse{}
+	if err := s.readFixture(venueSlug, "static.json", staticData); err != nil {
+		return nil, nil, err
+	}
+	if staticData.VenueRaw == nil {
+		return nil, nil, fmt.Errorf("static fixture of venue %s has no venue_raw", venueSlug)
+	}
+
+	dynamicData := &models.VenueDynamicResponse{}
+	if err := s.readFixture(venueSlug, "dynamic.json", dynamicData); err != nil {
+		return nil, nil, err
+	}
+	if dynamicData.VenueRaw == nil {
+		return nil, nil, fmt.Errorf("dynamic fixture of venue %s has no venue_raw", venueSlug)
+	}
+
+	return staticData, dynamicData, nil
+}
+
+// readFixture decodes the named fixture file of the venue into v.
+func (s *FileVenueSource) readFixture(venueSlug, name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, venueSlug, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("venue %s: %w", venueSlug, ErrVenueNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("could not read fixture %s of venue %s: %w", name, venueSlug, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("could not decode fixture %s of venue %s: %w", name, venueSlug, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFixture writes a fixture file of the venue into dir.
+func writeFixture(t *testing.T, dir, venueSlug, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, venueSlug), 0o755); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, venueSlug, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+}
+
+func TestFileVenueSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "venue", "static.json", `{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`)
+	writeFixture(t, dir, "venue", "dynamic.json", `{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000, "delivery_pricing": {"base_price": 190}}}}`)
+
+	source, err := NewFileVenueSource(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	staticData, dynamicData, err := source.GetVenueInformation(context.Background(), "venue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []float64{24.9354, 60.1699}, staticData.VenueRaw.Location.Coordinates)
+	assert.Equal(t, 190, dynamicData.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice)
+}
+
+func TestFileVenueSource_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "no-dynamic", "static.json", `{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`)
+	writeFixture(t, dir, "malformed", "static.json", `{"venue_raw": `)
+	writeFixture(t, dir, "empty", "static.json", `{}`)
+
+	source, err := NewFileVenueSource(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, venueSlug := range []string{"unknown", "no-dynamic", "..", "../etc"} {
+		_, _, err := source.GetVenueInformation(context.Background(), venueSlug)
+		assert.ErrorIs(t, err, ErrVenueNotFound, venueSlug)
+	}
+	for _, venueSlug := range []string{"malformed", "empty"} {
+		_, _, err := source.GetVenueInformation(context.Background(), venueSlug)
+		assert.Error(t, err, venueSlug)
+		assert.NotErrorIs(t, err, ErrVenueNotFound, venueSlug)
+	}
+}
+
+func TestNewFileVenueSource_MissingDir(t *testing.T) {
+	_, err := NewFileVenueSource(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Freshness states reported for venue API lookups when the HTTP cache is enabled.
+const (
+	FreshnessFresh       = "fresh"       // Served from the cache within its max-age.
+	FreshnessStale       = "stale"       // Served from the cache while it is revalidated in the background.
+	FreshnessRevalidated = "revalidated" // The venue API confirmed the cached copy with 304 Not Modified.
+	FreshnessMiss        = "miss"        // Fetched from the venue API.
+)
+
+// httpCacheName is the cache name reported for HTTP cache lookups.
+const httpCacheName = "http"
+
+// backgroundRefreshTimeout bounds a revalidation running after the request that triggered it returned.
+const backgroundRefreshTimeout = 10 * time.Second
+
+// cachedResponse is a venue API response kept by the HTTP cache.
+type cachedResponse struct {
+	body         []byte
+	etag         string    // ETag validator, sent back as If-None-Match.
+	lastModified string    // Last-Modified validator, sent back as If-Modified-Since.
+	fetchedAt    time.Time // Time the response was last fetched or confirmed by the venue API.
+	freshUntil   time.Time // Soft expiry: the response is served without revalidation until then.
+	staleUntil   time.Time // Hard expiry: the response is served while revalidating until then.
+	revalidating bool      // Set while a background revalidation is in flight.
+}
+
+// responseCache keeps venue API responses by URL and applies the venue API's caching headers.
+type responseCache struct {
+	mu                          sync.Mutex
+	entries                     map[string]*cachedResponse
+	defaultMaxAge               time.Duration // Max-age used when the response has no Cache-Control max-age.
+	defaultStaleWhileRevalidate time.Duration // Stale window used when the response has no stale-while-revalidate.
+}
+
+// WithHTTPCache caches venue API responses according to their Cache-Control, ETag and Last-Modified
+// headers. Expired responses are revalidated with If-None-Match and If-Modified-Since, and
+// responses past their soft expiry are served while being refreshed in the background.
+// The defaults apply when the venue API does not send the corresponding Cache-Control directive.
+func WithHTTPCache(defaultMaxAge, defaultStaleWhileRevalidate time.Duration) Option {
+	return func(v *VenueProvider) {
+		v.httpCache = &responseCache{
+			entries:                     make(map[string]*cachedResponse),
+			defaultMaxAge:               defaultMaxAge,
+			defaultStaleWhileRevalidate: defaultStaleWhileRevalidate,
+		}
+	}
+}
+
+// lookup returns a copy of the cached response for url and its freshness at now.
+// A nil response means the URL is not cached.
+func (c *responseCache) lookup(url string, now time.Time) (*cachedResponse, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	if !ok {
+		return nil, FreshnessMiss
+	}
+	copied := *entry
+	switch {
+	case now.Before(entry.freshUntil):
+		return &copied, FreshnessFresh
+	case now.Before(entry.staleUntil):
+		return &copied, FreshnessStale
+	default:
+		return &copied, FreshnessMiss
+	}
+}
+
+// startRevalidation marks url as being revalidated and reports whether the caller should do it,
+// so that only one background refresh runs per URL.
+func (c *responseCache) startRevalidation(url string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	if !ok || entry.revalidating {
+		return false
+	}
+	entry.revalidating = true
+	return true
+}
+
+// claimRefresh marks url as being revalidated if its cached response goes stale before deadline,
+// and returns a copy of the response to revalidate. It reports false when the URL is not cached
+// or is already being revalidated.
+func (c *responseCache) claimRefresh(url string, deadline time.Time) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	if !ok || entry.revalidating || entry.freshUntil.After(deadline) {
+		return nil, false
+	}
+	entry.revalidating = true
+	copied := *entry
+	return &copied, true
+}
+
+// endRevalidation clears the revalidation mark of url after a failed refresh.
+func (c *responseCache) endRevalidation(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[url]; ok {
+		entry.revalidating = false
+	}
+}
+
+// store caches a 200 response, or drops the entry if the response must not be stored.
+func (c *responseCache) store(url string, body []byte, header http.Header, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if directives.noStore {
+		delete(c.entries, url)
+		return
+	}
+	entry := &cachedResponse{
+		body:         body,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		fetchedAt:    now,
+	}
+	c.setExpiry(entry, directives, now)
+	c.entries[url] = entry
+}
+
+// refresh extends the lifetime of the cached response of url after a 304 Not Modified.
+func (c *responseCache) refresh(url string, header http.Header, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	if !ok {
+		return
+	}
+	if etag := header.Get("ETag"); etag != "" {
+		entry.etag = etag
+	}
+	entry.fetchedAt = now
+	c.setExpiry(entry, parseCacheControl(header.Get("Cache-Control")), now)
+	entry.revalidating = false
+}
+
+// setExpiry computes the soft and hard expiry of the entry from the Cache-Control directives.
+func (c *responseCache) setExpiry(entry *cachedResponse, directives cacheControl, now time.Time) {
+	maxAge := c.defaultMaxAge
+	if directives.maxAge >= 0 {
+		maxAge = directives.maxAge
+	}
+	staleWhileRevalidate := c.defaultStaleWhileRevalidate
+	if directives.staleWhileRevalidate >= 0 {
+		staleWhileRevalidate = directives.staleWhileRevalidate
+	}
+	if directives.noCache {
+		maxAge = 0
+	}
+	if directives.noCache || directives.mustRevalidate {
+		staleWhileRevalidate = 0
+	}
+
+	entry.freshUntil = now.Add(maxAge)
+	entry.staleUntil = entry.freshUntil.Add(staleWhileRevalidate)
+}
+
+// cacheControl holds the Cache-Control directives used by the HTTP cache.
+// Durations are negative when the directive is absent.
+type cacheControl struct {
+	maxAge               time.Duration
+	staleWhileRevalidate time.Duration
+	noStore              bool
+	noCache              bool
+	mustRevalidate       bool
+}
+
+// parseCacheControl parses a Cache-Control header value. Unknown directives are ignored.
+func parseCacheControl(value string) cacheControl {
+	directives := cacheControl{maxAge: -1, staleWhileRevalidate: -1}
+	for _, part := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch strings.ToLower(name) {
+		case "max-age":
+			directives.maxAge = parseSeconds(arg)
+		case "stale-while-revalidate":
+			directives.staleWhileRevalidate = parseSeconds(arg)
+		case "no-store":
+			directives.noStore = true
+		case "no-cache":
+			directives.noCache = true
+		case "must-revalidate":
+			directives.mustRevalidate = true
+		}
+	}
+	return directives
+}
+
+// parseSeconds parses a delta-seconds directive argument, returning -1 if it is invalid.
+func parseSeconds(arg string) time.Duration {
+	seconds, err := strconv.Atoi(strings.Trim(arg, `"`))
+	if err != nil || seconds < 0 {
+		return -1
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// cachedCall serves url from the HTTP cache when possible, with the time the response was fetched.
+// Responses past their soft expiry are served immediately and refreshed in the background; expired
+// or missing ones are fetched, with conditional headers when validators are known.
+func (v *VenueProvider) cachedCall(ctx context.Context, endpoint, url string) ([]byte, time.Time, error) {
+	cached, state := v.httpCache.lookup(url, v.now())
+	switch state {
+	case FreshnessFresh:
+		v.observeFreshness(endpoint, state)
+		return cached.body, cached.fetchedAt, nil
+	case FreshnessStale:
+		if v.httpCache.startRevalidation(url) && !v.goRevalidate(ctx, endpoint, url, cached) {
+			v.httpCache.endRevalidation(url)
+		}
+		v.observeFreshness(endpoint, state)
+		return cached.body, cached.fetchedAt, nil
+	}
+	return v.revalidate(ctx, endpoint, url, cached)
+}
+
+// goRevalidate revalidates url in a background goroutine, which outlives the request carried by
+// ctx but is cancelled by StopRevalidations. It reports false, starting nothing, once
+// StopRevalidations was called.
+func (v *VenueProvider) goRevalidate(ctx context.Context, endpoint, url string, cached *cachedResponse) bool {
+	v.revalidateMu.Lock()
+	defer v.revalidateMu.Unlock()
+	if v.background.Err() != nil {
+		return false
+	}
+
+	v.revalidateWG.Add(1)
+	go func() {
+		defer v.revalidateWG.Done()
+		bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundRefreshTimeout)
+		defer cancel()
+		stop := context.AfterFunc(v.background, cancel)
+		defer stop()
+		_, _, _ = v.revalidate(bgCtx, endpoint, url, cached)
+	}()
+	return true
+}
+
+// StopRevalidations cancels the background revalidations of the HTTP cache and waits for them to
+// return until ctx is done. Stale responses are still served afterwards, without revalidation.
+func (v *VenueProvider) StopRevalidations(ctx context.Context) error {
+	v.revalidateMu.Lock()
+	v.stopBackground()
+	v.revalidateMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		v.revalidateWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background revalidations did not stop: %w", ctx.Err())
+	}
+}
+
+// revalidate fetches url, sending the validators of the cached response if there is one, and
+// returns the body with the time it was fetched or confirmed.
+func (v *VenueProvider) revalidate(ctx context.Context, endpoint, url string, cached *cachedResponse) ([]byte, time.Time, error) {
+	header := http.Header{}
+	if cached != nil {
+		if cached.etag != "" {
+			header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := v.doRequest(ctx, endpoint, url, header)
+	if err != nil {
+		v.httpCache.endRevalidation(url)
+		return nil, time.Time{}, err
+	}
+
+	now := v.now()
+	if resp.status == http.StatusNotModified && cached != nil {
+		v.httpCache.refresh(url, resp.header, now)
+		v.observeFreshness(endpoint, FreshnessRevalidated)
+		return cached.body, now, nil
+	}
+	v.httpCache.store(url, resp.body, resp.header, now)
+	v.observeFreshness(endpoint, FreshnessMiss)
+	return resp.body, now, nil
+}
+
+// observeFreshness reports the freshness state of a lookup and whether the cache could serve it.
+func (v *VenueProvider) observeFreshness(endpoint, state string) {
+	v.recorder.ObserveFreshness(endpoint, state)
+	v.recorder.ObserveCache(httpCacheName, state != FreshnessMiss)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	tests := []struct {
+		value string
+		want  cacheControl
+	}{
+		{value: "", want: cacheControl{maxAge: -1, staleWhileRevalidate: -1}},
+		{value: "max-age=60, stale-while-revalidate=30", want: cacheControl{maxAge: time.Minute, staleWhileRevalidate: 30 * time.Second}},
+		{value: "no-store", want: cacheControl{maxAge: -1, staleWhileRevalidate: -1, noStore: true}},
+		{value: "No-Cache, max-age=\"10\"", want: cacheControl{maxAge: 10 * time.Second, staleWhileRevalidate: -1, noCache: true}},
+		{value: "max-age=abc, must-revalidate", want: cacheControl{maxAge: -1, staleWhileRevalidate: -1, mustRevalidate: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseCacheControl(tt.value))
+		})
+	}
+}
+
+// conditionalVenueServer serves the dynamic endpoint with caching headers and records the requests.
+type conditionalVenueServer struct {
+	mu           sync.Mutex
+	cacheControl string
+	etag         string
+	requests     []http.Header
+	server       *httptest.Server
+}
+
+func newConditionalVenueServer(t *testing.T, cacheControl string) *conditionalVenueServer {
+	t.Helper()
+	s := &conditionalVenueServer{cacheControl: cacheControl, etag: `"v1"`}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r.Header.Clone())
+
+		w.Header().Set("Cache-Control", s.cacheControl)
+		w.Header().Set("ETag", s.etag)
+		if r.Header.Get("If-None-Match") == s.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if strings.Contains(r.URL.Path, "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000, "delivery_pricing": {"base_price": 190}}}}`))
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *conditionalVenueServer) requestHeaders() []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]http.Header(nil), s.requests...)
+}
+
+func TestHTTPCache_FreshAndRevalidated(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "max-age=60")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &fakeRecorder{}
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(0, 0), WithRecorder(recorder))
+	provider.now = func() time.Time { return now }
+	url := upstream.server.URL + "/venue/dynamic"
+
+	_, err := provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+
+	// Within max-age the cached response is served without calling upstream.
+	now = now.Add(30 * time.Second)
+	_, err = provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+	assert.Len(t, upstream.requestHeaders(), 1)
+
+	// Once expired, the response is revalidated with its ETag.
+	now = now.Add(time.Minute)
+	data, err := provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+	assert.Equal(t, 190, data.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice)
+
+	requests := upstream.requestHeaders()
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, `"v1"`, requests[1].Get("If-None-Match"))
+	}
+	assert.Equal(t, map[string]int{FreshnessMiss: 1, FreshnessFresh: 1, FreshnessRevalidated: 1}, recorder.freshness)
+}
+
+func TestHTTPCache_StaleWhileRevalidate(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "max-age=60, stale-while-revalidate=60")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(0, 0))
+	provider.now = func() time.Time { return now }
+	url := upstream.server.URL + "/venue/dynamic"
+
+	_, err := provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+
+	// Past the soft expiry the cached response is served immediately and refreshed in the background.
+	upstream.mu.Lock()
+	upstream.etag = `"v2"`
+	upstream.mu.Unlock()
+	now = now.Add(90 * time.Second)
+	_, err = provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		cached, state := provider.httpCache.lookup(url, now)
+		return cached.etag == `"v2"` && state == FreshnessFresh
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, upstream.requestHeaders(), 2)
+}
+
+func TestHTTPCache_StopRevalidations(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		mu.Unlock()
+		if !first {
+			// The revalidation hangs until it is cancelled.
+			close(started)
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=60")
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"delivery_pricing": {"base_price": 190}}}}`))
+	}))
+	t.Cleanup(server.Close)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewVenueProvider(server.URL, WithHTTPCache(0, 0))
+	provider.now = func() time.Time { return now }
+	url := server.URL + "/venue/dynamic"
+
+	_, err := provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+	now = now.Add(90 * time.Second)
+	_, err = provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+	<-started
+
+	// Stopping cancels the revalidation in flight and waits for it.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, provider.StopRevalidations(ctx))
+
+	// Stale responses are still served, without starting new revalidations.
+	_, err = provider.FetchVenueDynamicData(context.Background(), url)
+	assert.NoError(t, err)
+	mu.Lock()
+	assert.Equal(t, 2, requests)
+	mu.Unlock()
+}
+
+func TestHTTPCache_NoStore(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "no-store")
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(time.Minute, time.Minute))
+	url := upstream.server.URL + "/venue/static"
+
+	for i := 0; i < 2; i++ {
+		_, err := provider.FetchVenueStaticData(context.Background(), url)
+		assert.NoError(t, err)
+	}
+
+	requests := upstream.requestHeaders()
+	assert.Len(t, requests, 2)
+	assert.Empty(t, requests[1].Get("If-None-Match"))
+}
+
+func TestHTTPCache_DefaultMaxAge(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "")
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(time.Minute, 0))
+	url := upstream.server.URL + "/venue/static"
+
+	for i := 0; i < 3; i++ {
+		_, err := provider.FetchVenueStaticData(context.Background(), url)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, upstream.requestHeaders(), 1)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"backend-wolt-go/internal/mockvenue"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var mockVenue = mockvenue.Venue{
+	Static:  []byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`),
+	Dynamic: []byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000, "delivery_pricing": {"base_price": 190}}}}`),
+}
+
+func TestVenueProvider_AgainstMockAPI(t *testing.T) {
+	tests := []struct {
+		name    string
+		slug    string
+		fault   *mockvenue.Fault
+		timeout time.Duration
+		opts    []Option
+		wantErr string
+	}{
+		{name: "Success", slug: "venue"},
+		{name: "UnknownVenue", slug: "unknown", wantErr: "404 Not Found"},
+		{name: "ServerError", slug: "venue", fault: &mockvenue.Fault{Status: http.StatusInternalServerError}, wantErr: "500 Internal Server Error"},
+		{name: "MalformedDynamic", slug: "venue", fault: &mockvenue.Fault{Endpoint: mockvenue.EndpointDynamic, Malformed: true}, wantErr: "failed to decode JSON response"},
+		{name: "Timeout", slug: "venue", fault: &mockvenue.Fault{Latency: time.Second}, timeout: 50 * time.Millisecond, wantErr: "context deadline exceeded"},
+		{name: "ClientTimeout", slug: "venue", fault: &mockvenue.Fault{Latency: time.Second}, opts: []Option{WithTimeout(50 * time.Millisecond)}, wantErr: ErrUpstreamTimeout.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := mockvenue.New(map[string]mockvenue.Venue{"venue": mockVenue})
+			if tt.fault != nil {
+				api.SetFault("", *tt.fault)
+			}
+			server := mockvenue.NewServer(t, api)
+			provider := NewVenueProvider(server.URL, tt.opts...)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			staticData, dynamicData, err := provider.GetVenueInformation(ctx, tt.slug)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{24.9354, 60.1699}, staticData.VenueRaw.Location.Coordinates)
+			assert.Equal(t, 190, dynamicData.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice)
+		})
+	}
+}
+
+func TestVenueProvider_RevalidatesAgainstMockAPI(t *testing.T) {
+	api := mockvenue.New(map[string]mockvenue.Venue{"venue": mockVenue})
+	server := mockvenue.NewServer(t, api)
+	recorder := &fakeRecorder{}
+	provider := NewVenueProvider(server.URL, WithHTTPCache(0, 0), WithRecorder(recorder))
+
+	for i := 0; i < 2; i++ {
+		_, _, err := provider.GetVenueInformation(context.Background(), "venue")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, api.Requests("venue", mockvenue.EndpointDynamic))
+	assert.Equal(t, 2, recorder.freshness[FreshnessRevalidated])
+}
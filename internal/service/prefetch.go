@@ -0,0 +1,136 @@
+package service
+
+import (
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Defaults used when the prefetch configuration leaves a setting unset.
+const (
+	defaultPrefetchInterval    = 5 * time.Second
+	defaultPrefetchTopK        = 20
+	defaultPrefetchLeadTime    = 10 * time.Second
+	defaultPrefetchConcurrency = 4
+)
+
+// PopularVenues ranks venue slugs by how often they were requested recently.
+type PopularVenues interface {
+	// Top returns up to k venue slugs, the most requested first.
+	Top(k int) []string
+	// Decay ages the request counts so that the ranking follows recent traffic.
+	Decay()
+}
+
+// Prefetcher keeps the HTTP cache warm for the most requested venues by refreshing their dynamic
+// data before it expires, so that their requests never wait for the venue API.
+type Prefetcher struct {
+	provider *VenueProvider
+	popular  PopularVenues
+	config   models.PrefetchConfig
+}
+
+// NewPrefetcher creates a Prefetcher refreshing the venues ranked by popular through the HTTP
+// cache of provider. Feed popular from the provider with WithPopularityTracker.
+func NewPrefetcher(provider *VenueProvider, popular PopularVenues, config models.PrefetchConfig) *Prefetcher {
+	if config.Interval <= 0 {
+		config.Interval = defaultPrefetchInterval
+	}
+	if config.TopK <= 0 {
+		config.TopK = defaultPrefetchTopK
+	}
+	if config.LeadTime <= 0 {
+		config.LeadTime = defaultPrefetchLeadTime
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultPrefetchConcurrency
+	}
+	return &Prefetcher{provider: provider, popular: popular, config: config}
+}
+
+// Run refreshes the popular venues every interval until ctx is done. It returns once the
+// refreshes in flight have finished, so the caller can wait for it on shutdown.
+func (p *Prefetcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.RefreshPopular(ctx)
+		}
+	}
+}
+
+// RefreshPopular refreshes the cached dynamic data of the most requested venues that expires
+// within the lead time, at most concurrency at a time, and returns the number of venues refreshed.
+// Venues that are not cached yet are left to regular requests. It does nothing when the provider
+// has no HTTP cache.
+func (p *Prefetcher) RefreshPopular(ctx context.Context) int {
+	if p.provider.httpCache == nil {
+		return 0
+	}
+	defer p.popular.Decay()
+
+	var (
+		wg        sync.WaitGroup
+		refreshed atomic.Int64
+		slots     = make(chan struct{}, p.config.Concurrency)
+		deadline  = p.provider.now().Add(p.config.LeadTime)
+	)
+	for _, venueSlug := range p.popular.Top(p.config.TopK) {
+		url := p.provider.dynamicURL(venueSlug)
+		cached, ok := p.provider.httpCache.claimRefresh(url, deadline)
+		if !ok {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			p.provider.httpCache.endRevalidation(url)
+			wg.Wait()
+			return int(refreshed.Load())
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := p.refresh(ctx, venueSlug, url, cached); err == nil {
+				refreshed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	return int(refreshed.Load())
+}
+
+// refresh revalidates the cached dynamic data of the venue, respecting the upstream limiter.
+func (p *Prefetcher) refresh(ctx context.Context, venueSlug, url string, cached *cachedResponse) (err error) {
+	ctx, span := tracer.Start(ctx, "Prefetcher.refresh", trace.WithAttributes(attribute.String("venue.slug", venueSlug)))
+	defer func() { endSpan(span, err) }()
+	logger := logging.FromContext(ctx).With(slog.String("venue_slug", venueSlug))
+
+	if _, _, err := p.provider.revalidate(ctx, EndpointDynamic, url, cached); err != nil {
+		var limitErr *ratelimit.LimitExceededError
+		if errors.As(err, &limitErr) {
+			logger.DebugContext(ctx, "venue prefetch skipped", slog.String("error", err.Error()))
+			return err
+		}
+		logger.WarnContext(ctx, "venue prefetch failed", slog.String("error", err.Error()))
+		return err
+	}
+	logger.DebugContext(ctx, "venue prefetched")
+	return nil
+}
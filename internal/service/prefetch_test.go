@@ -0,0 +1,130 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/topk"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefetcher_RefreshesPopularVenues(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "max-age=60")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	popular := topk.New(10)
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(0, 0), WithPopularityTracker(popular))
+	provider.now = func() time.Time { return now }
+
+	for _, venueSlug := range []string{"hot", "hot", "hot", "cold"} {
+		_, _, err := provider.GetVenueInformation(context.Background(), venueSlug)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, upstream.requestHeaders(), 4)
+
+	// Only the most requested venue is revalidated once its dynamic data is about to expire.
+	now = now.Add(55 * time.Second)
+	prefetcher := NewPrefetcher(provider, popular, models.PrefetchConfig{TopK: 1, LeadTime: 10 * time.Second})
+	assert.Equal(t, 1, prefetcher.RefreshPopular(context.Background()))
+
+	requests := upstream.requestHeaders()
+	if assert.Len(t, requests, 5) {
+		assert.Equal(t, `"v1"`, requests[4].Get("If-None-Match"))
+	}
+
+	// The refreshed data is served from the cache past its original expiry.
+	now = now.Add(15 * time.Second)
+	_, err := provider.FetchVenueDynamicData(context.Background(), upstream.server.URL+"/hot/dynamic")
+	assert.NoError(t, err)
+	assert.Len(t, upstream.requestHeaders(), 5)
+}
+
+func TestPrefetcher_SkipsFreshAndUncachedVenues(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "max-age=60")
+	popular := topk.New(10)
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(0, 0), WithPopularityTracker(popular))
+
+	_, _, err := provider.GetVenueInformation(context.Background(), "cached")
+	assert.NoError(t, err)
+	popular.Add("uncached")
+
+	prefetcher := NewPrefetcher(provider, popular, models.PrefetchConfig{LeadTime: 10 * time.Second})
+	assert.Equal(t, 0, prefetcher.RefreshPopular(context.Background()))
+	assert.Len(t, upstream.requestHeaders(), 2)
+}
+
+func TestPrefetcher_WithoutHTTPCache(t *testing.T) {
+	popular := topk.New(10)
+	popular.Add("venue")
+	provider := NewVenueProvider("http://127.0.0.1:0")
+
+	prefetcher := NewPrefetcher(provider, popular, models.PrefetchConfig{})
+	assert.Equal(t, 0, prefetcher.RefreshPopular(context.Background()))
+}
+
+func TestPrefetcher_BoundedConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if strings.HasSuffix(r.URL.Path, "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000}}}`))
+	}))
+	defer server.Close()
+
+	popular := topk.New(10)
+	provider := NewVenueProvider(server.URL, WithHTTPCache(0, time.Minute), WithPopularityTracker(popular))
+	for i := 0; i < 6; i++ {
+		_, _, err := provider.GetVenueInformation(context.Background(), fmt.Sprintf("venue-%d", i))
+		assert.NoError(t, err)
+	}
+
+	prefetcher := NewPrefetcher(provider, popular, models.PrefetchConfig{Concurrency: 2})
+	assert.Equal(t, 6, prefetcher.RefreshPopular(context.Background()))
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestPrefetcher_RunStopsOnCancel(t *testing.T) {
+	upstream := newConditionalVenueServer(t, "max-age=0")
+	popular := topk.New(10)
+	provider := NewVenueProvider(upstream.server.URL, WithHTTPCache(0, time.Minute), WithPopularityTracker(popular))
+	_, _, err := provider.GetVenueInformation(context.Background(), "venue")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	prefetcher := NewPrefetcher(provider, popular, models.PrefetchConfig{Interval: time.Millisecond})
+	done := make(chan struct{})
+	go func() {
+		prefetcher.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(upstream.requestHeaders()) > 2 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("prefetcher did not stop after cancellation")
+	}
+}
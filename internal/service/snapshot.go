@@ -0,0 +1,191 @@
+package service
+
+import (
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// snapshotCache is the cache name reported for snapshot lookups.
+const snapshotCache = "snapshot"
+
+// WarmSnapshots loads the persisted snapshots so that venues can be priced from stale data even
+// when the venue API is down right after a restart. Requests are served while the snapshots load,
+// so the static and dynamic parts fetched since then are kept when they are newer than the loaded
+// ones.
+func (v *VenueProvider) WarmSnapshots(ctx context.Context) error {
+	defer v.warmed.Store(true)
+	if v.snapshotStore == nil {
+		return nil
+	}
+
+	snapshots, err := v.snapshotStore.LoadAll()
+	v.snapshotMu.Lock()
+	for venueSlug, loaded := range snapshots {
+		current, ok := v.snapshots[venueSlug]
+		if !ok {
+			v.snapshots[venueSlug] = loaded
+			continue
+		}
+		snapshot := *current
+		if loaded.Static != nil && loaded.StaticFetchedAt.After(snapshot.StaticFetchedAt) {
+			snapshot.Static, snapshot.StaticFetchedAt = loaded.Static, loaded.StaticFetchedAt
+		}
+		if loaded.Dynamic != nil && loaded.DynamicFetchedAt.After(snapshot.DynamicFetchedAt) {
+			snapshot.Dynamic, snapshot.DynamicFetchedAt = loaded.Dynamic, loaded.DynamicFetchedAt
+		}
+		v.snapshots[venueSlug] = &snapshot
+	}
+	v.snapshotMu.Unlock()
+
+	logging.FromContext(ctx).InfoContext(ctx, "venue snapshots loaded", slog.Int("count", len(snapshots)))
+	if err != nil {
+		return fmt.Errorf("failed to load some venue snapshots: %w", err)
+	}
+	return nil
+}
+
+// SnapshotsWarmed reports whether WarmSnapshots has completed. It is used as a readiness check.
+func (v *VenueProvider) SnapshotsWarmed(context.Context) error {
+	if !v.warmed.Load() {
+		return fmt.Errorf("venue snapshots not loaded yet")
+	}
+	return nil
+}
+
+// storeSnapshot records the parts of the venue information fetched from the venue API since the
+// last snapshot, and persists the snapshot in the background. Data served from the HTTP cache
+// keeps the time it was fetched, so it does not change the snapshot.
+func (v *VenueProvider) storeSnapshot(ctx context.Context, venueSlug string, staticData *models.VenueStaticResponse, dynamicData *models.VenueDynamicResponse) {
+	if v.snapshotStore == nil {
+		return
+	}
+
+	v.snapshotMu.Lock()
+	defer v.snapshotMu.Unlock()
+	snapshot := &VenueSnapshot{}
+	if previous, ok := v.snapshots[venueSlug]; ok {
+		*snapshot = *previous
+	}
+	changed := false
+	if !staticData.Stale && staticData.FetchedAt.After(snapshot.StaticFetchedAt) {
+		snapshot.Static, snapshot.StaticFetchedAt = staticData, staticData.FetchedAt
+		changed = true
+	}
+	if !dynamicData.Stale && dynamicData.FetchedAt.After(snapshot.DynamicFetchedAt) {
+		snapshot.Dynamic, snapshot.DynamicFetchedAt = dynamicData, dynamicData.FetchedAt
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	v.snapshots[venueSlug] = snapshot
+
+	// A single writer per venue persists the latest snapshot, so that an older snapshot never
+	// overwrites a newer one on disk.
+	if !v.persisting[venueSlug] {
+		v.persisting[venueSlug] = true
+		v.persistWG.Add(1)
+		go v.persistSnapshots(context.WithoutCancel(ctx), venueSlug)
+	}
+}
+
+// persistSnapshots saves the snapshot of the venue until the saved one is the latest.
+func (v *VenueProvider) persistSnapshots(ctx context.Context, venueSlug string) {
+	defer v.persistWG.Done()
+
+	var saved *VenueSnapshot
+	for {
+		v.snapshotMu.Lock()
+		snapshot := v.snapshots[venueSlug]
+		if snapshot == saved {
+			delete(v.persisting, venueSlug)
+			v.snapshotMu.Unlock()
+			return
+		}
+		v.snapshotMu.Unlock()
+
+		if err := v.snapshotStore.Save(venueSlug, snapshot); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "failed to persist venue snapshot",
+				slog.String("venue_slug", venueSlug),
+				slog.String("error", err.Error()),
+			)
+		}
+		saved = snapshot
+	}
+}
+
+// FlushSnapshots waits until the snapshots recorded so far are persisted, or until ctx is done.
+func (v *VenueProvider) FlushSnapshots(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		v.persistWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("venue snapshots not persisted: %w", ctx.Err())
+	}
+}
+
+// staleStatic returns a copy of the snapshot's static data, marked stale, if it is recent enough.
+// Otherwise the upstream error is returned.
+func (v *VenueProvider) staleStatic(ctx context.Context, venueSlug string, upstreamErr error) (*models.VenueStaticResponse, error) {
+	snapshot := v.snapshot(venueSlug)
+	if snapshot == nil || snapshot.Static == nil || !v.fresh(snapshot.StaticFetchedAt) {
+		v.recorder.ObserveCache(snapshotCache, false)
+		return nil, upstreamErr
+	}
+	v.recorder.ObserveCache(snapshotCache, true)
+	logStale(ctx, venueSlug, EndpointStatic, snapshot.StaticFetchedAt, upstreamErr)
+
+	data := *snapshot.Static
+	data.Stale, data.FetchedAt = true, snapshot.StaticFetchedAt
+	return &data, nil
+}
+
+// staleDynamic returns a copy of the snapshot's dynamic data, marked stale, if it is recent enough.
+// Otherwise the upstream error is returned.
+func (v *VenueProvider) staleDynamic(ctx context.Context, venueSlug string, upstreamErr error) (*models.VenueDynamicResponse, error) {
+	snapshot := v.snapshot(venueSlug)
+	if snapshot == nil || snapshot.Dynamic == nil || !v.fresh(snapshot.DynamicFetchedAt) {
+		v.recorder.ObserveCache(snapshotCache, false)
+		return nil, upstreamErr
+	}
+	v.recorder.ObserveCache(snapshotCache, true)
+	logStale(ctx, venueSlug, EndpointDynamic, snapshot.DynamicFetchedAt, upstreamErr)
+
+	data := *snapshot.Dynamic
+	data.Stale, data.FetchedAt = true, snapshot.DynamicFetchedAt
+	return &data, nil
+}
+
+// snapshot returns the snapshot of the venue, or nil if snapshots are disabled or missing.
+func (v *VenueProvider) snapshot(venueSlug string) *VenueSnapshot {
+	if v.snapshotStore == nil {
+		return nil
+	}
+	v.snapshotMu.RLock()
+	defer v.snapshotMu.RUnlock()
+	return v.snapshots[venueSlug]
+}
+
+// fresh reports whether data fetched at fetchedAt may still be served.
+func (v *VenueProvider) fresh(fetchedAt time.Time) bool {
+	return v.now().Sub(fetchedAt) <= v.snapshotMaxAge
+}
+
+// logStale logs that stale data is served in place of a failed upstream call.
+func logStale(ctx context.Context, venueSlug, endpoint string, fetchedAt time.Time, upstreamErr error) {
+	logging.FromContext(ctx).WarnContext(ctx, "serving stale venue data",
+		slog.String("venue_slug", venueSlug),
+		slog.String("endpoint", endpoint),
+		slog.Time("fetched_at", fetchedAt),
+		slog.String("error", upstreamErr.Error()),
+	)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// VenueSnapshot is the last known good venue information fetched from the venue API.
+type VenueSnapshot struct {
+	Static           *models.VenueStaticResponse  `json:"static,omitempty"`   // Last successful static response.
+	StaticFetchedAt  time.Time                    `json:"static_fetched_at"`  // Time the static response was fetched.
+	Dynamic          *models.VenueDynamicResponse `json:"dynamic,omitempty"`  // Last successful dynamic response.
+	DynamicFetchedAt time.Time                    `json:"dynamic_fetched_at"` // Time the dynamic response was fetched.
+}
+
+// SnapshotStore persists venue snapshots across restarts.
+type SnapshotStore interface {
+	// Save stores the snapshot of the venue, replacing any previous one.
+	Save(venueSlug string, snapshot *VenueSnapshot) error
+	// LoadAll returns every stored snapshot by venue slug.
+	LoadAll() (map[string]*VenueSnapshot, error)
+}
+
+// FileSnapshotStore is a SnapshotStore keeping one JSON file per venue in a directory.
+type FileSnapshotStore struct {
+	dir string
+}
+
+// snapshotExt is the file extension of the snapshot files.
+const snapshotExt = ".json"
+
+// NewFileSnapshotStore creates a FileSnapshotStore in dir, creating the directory if needed.
+func NewFileSnapshotStore(dir string) (*FileSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create snapshot directory: %w", err)
+	}
+	return &FileSnapshotStore{dir: dir}, nil
+}
+
+// Save writes the snapshot atomically, so that a crash never leaves a truncated file behind.
+func (s *FileSnapshotStore) Save(venueSlug string, snapshot *VenueSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "snapshot-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(venueSlug)); err != nil {
+		return fmt.Errorf("failed to replace snapshot file: %w", err)
+	}
+	return nil
+}
+
+// LoadAll reads every snapshot file in the directory. Unreadable files are skipped and reported
+// in the returned error, so that one corrupt file does not prevent warming the others.
+func (s *FileSnapshotStore) LoadAll() (map[string]*VenueSnapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read snapshot directory: %w", err)
+	}
+
+	snapshots := make(map[string]*VenueSnapshot, len(entries))
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, snapshotExt) {
+			continue
+		}
+		venueSlug, err := url.PathUnescape(strings.TrimSuffix(name, snapshotExt))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid snapshot file name %q: %w", name, err))
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not read snapshot %q: %w", name, err))
+			continue
+		}
+		snapshot := &VenueSnapshot{}
+		if err := json.Unmarshal(data, snapshot); err != nil {
+			errs = append(errs, fmt.Errorf("could not decode snapshot %q: %w", name, err))
+			continue
+		}
+		snapshots[venueSlug] = snapshot
+	}
+	return snapshots, errors.Join(errs...)
+}
+
+// path returns the file of the venue. The slug is escaped so it can never leave the directory.
+func (s *FileSnapshotStore) path(venueSlug string) string {
+	return filepath.Join(s.dir, url.PathEscape(venueSlug)+snapshotExt)
+}
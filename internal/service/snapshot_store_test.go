@@ -0,0 +1,60 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSnapshotStore_SaveAndLoad(t *testing.T) {
+	store, err := NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshots"))
+	assert.NoError(t, err)
+
+	fetchedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := &VenueSnapshot{DynamicFetchedAt: fetchedAt}
+	snapshot.Static = decodeStatic(t, `{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`)
+	snapshot.StaticFetchedAt = fetchedAt
+
+	assert.NoError(t, store.Save("venue-a", snapshot))
+	assert.NoError(t, store.Save("../escape/attempt", snapshot))
+
+	loaded, err := store.LoadAll()
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, []float64{24.9354, 60.1699}, loaded["venue-a"].Static.VenueRaw.Location.Coordinates)
+	assert.True(t, fetchedAt.Equal(loaded["venue-a"].StaticFetchedAt))
+	assert.Contains(t, loaded, "../escape/attempt")
+
+	// Slugs are escaped so that every snapshot stays inside the directory.
+	entries, err := os.ReadDir(store.dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestFileSnapshotStore_SkipsCorruptFiles(t *testing.T) {
+	store, err := NewFileSnapshotStore(t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Save("venue-a", &VenueSnapshot{}))
+	assert.NoError(t, os.WriteFile(filepath.Join(store.dir, "venue-b.json"), []byte("{"), 0o644))
+
+	loaded, err := store.LoadAll()
+	assert.Error(t, err)
+	assert.Contains(t, loaded, "venue-a")
+	assert.NotContains(t, loaded, "venue-b")
+}
+
+// decodeStatic decodes a static venue response fixture.
+func decodeStatic(t *testing.T, data string) *models.VenueStaticResponse {
+	t.Helper()
+	static := &models.VenueStaticResponse{}
+	if err := json.Unmarshal([]byte(data), static); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+	return static
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyVenueServer serves valid venue data until failing is set.
+func flakyVenueServer(t *testing.T, failing *atomic.Bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if strings.Contains(r.URL.String(), "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 1000, "delivery_pricing": {"base_price": 190}}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetVenueInformation_ServesStaleSnapshot(t *testing.T) {
+	var failing atomic.Bool
+	server := flakyVenueServer(t, &failing)
+	store, err := NewFileSnapshotStore(t.TempDir())
+	assert.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorder := &fakeRecorder{}
+	provider := NewVenueProvider(server.URL, WithSnapshots(store, time.Hour), WithRecorder(recorder))
+	provider.now = func() time.Time { return now }
+
+	staticData, dynamicData, err := provider.GetVenueInformation(context.Background(), "venue-a")
+	assert.NoError(t, err)
+	assert.False(t, staticData.Stale || dynamicData.Stale)
+
+	// The upstream fails: the snapshot is served, marked stale.
+	failing.Store(true)
+	now = now.Add(30 * time.Minute)
+	staticData, dynamicData, err = provider.GetVenueInformation(context.Background(), "venue-a")
+	assert.NoError(t, err)
+	assert.True(t, staticData.Stale)
+	assert.True(t, dynamicData.Stale)
+	assert.Equal(t, 190, dynamicData.VenueRaw.DeliverySpecs.DeliveryPricing.BasePrice)
+	assert.Equal(t, 2, recorder.cacheHits[true])
+
+	// Past the maximum age the upstream error is returned.
+	now = now.Add(time.Hour)
+	_, _, err = provider.GetVenueInformation(context.Background(), "venue-a")
+	assert.Error(t, err)
+
+	// Unknown venues are never served from snapshots.
+	_, _, err = provider.GetVenueInformation(context.Background(), "venue-b")
+	assert.Error(t, err)
+}
+
+func TestWarmSnapshots(t *testing.T) {
+	var failing atomic.Bool
+	server := flakyVenueServer(t, &failing)
+	dir := t.TempDir()
+	store, err := NewFileSnapshotStore(dir)
+	assert.NoError(t, err)
+
+	// A first instance fetches and persists the venue in the background.
+	first := NewVenueProvider(server.URL, WithSnapshots(store, time.Hour))
+	_, _, err = first.GetVenueInformation(context.Background(), "venue-a")
+	assert.NoError(t, err)
+	assert.NoError(t, first.FlushSnapshots(context.Background()))
+
+	// A restarted instance warms from disk and survives an upstream outage.
+	failing.Store(true)
+	restarted := NewVenueProvider(server.URL, WithSnapshots(store, time.Hour))
+	assert.Error(t, restarted.SnapshotsWarmed(context.Background()))
+	assert.NoError(t, restarted.WarmSnapshots(context.Background()))
+	assert.NoError(t, restarted.SnapshotsWarmed(context.Background()))
+
+	_, dynamicData, err := restarted.GetVenueInformation(context.Background(), "venue-a")
+	assert.NoError(t, err)
+	assert.True(t, dynamicData.Stale)
+}
+
+func TestWarmSnapshots_KeepsNewerData(t *testing.T) {
+	store, err := NewFileSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open snapshot store: %v", err)
+	}
+	older := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	persisted := &VenueSnapshot{
+		Static:           &models.VenueStaticResponse{},
+		StaticFetchedAt:  newer,
+		Dynamic:          &models.VenueDynamicResponse{},
+		DynamicFetchedAt: older,
+	}
+	for _, venueSlug := range []string{"venue-a", "venue-b"} {
+		if err := store.Save(venueSlug, persisted); err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+	}
+
+	// venue-a was fetched while the snapshots were loading: its newer dynamic part is kept, and
+	// its older static part is replaced by the loaded one.
+	provider := NewVenueProvider("http://venues.invalid", WithSnapshots(store, time.Hour))
+	fetched := &VenueSnapshot{
+		Static:           &models.VenueStaticResponse{},
+		StaticFetchedAt:  older,
+		Dynamic:          &models.VenueDynamicResponse{},
+		DynamicFetchedAt: newer,
+	}
+	provider.snapshots["venue-a"] = fetched
+
+	assert.NoError(t, provider.WarmSnapshots(context.Background()))
+	snapshot := provider.snapshot("venue-a")
+	assert.Equal(t, newer, snapshot.StaticFetchedAt)
+	assert.Equal(t, newer, snapshot.DynamicFetchedAt)
+	assert.Same(t, fetched.Dynamic, snapshot.Dynamic)
+	assert.Equal(t, older, fetched.StaticFetchedAt, "the snapshot in use is not modified")
+
+	// Venues not fetched yet get the loaded snapshot.
+	assert.Equal(t, older, provider.snapshot("venue-b").DynamicFetchedAt)
+}
+
+func TestGetVenueInformation_WithoutSnapshots(t *testing.T) {
+	var failing atomic.Bool
+	server := flakyVenueServer(t, &failing)
+	provider := NewVenueProvider(server.URL)
+
+	_, _, err := provider.GetVenueInformation(context.Background(), "venue-a")
+	assert.NoError(t, err)
+
+	failing.Store(true)
+	_, _, err = provider.GetVenueInformation(context.Background(), "venue-a")
+	assert.Error(t, err)
+	assert.NoError(t, provider.WarmSnapshots(context.Background()))
+}
+
+// countingSnapshotStore counts the snapshots saved per venue.
+type countingSnapshotStore struct {
+	mu    sync.Mutex
+	saved map[string][]*VenueSnapshot
+}
+
+func (s *countingSnapshotStore) Save(venueSlug string, snapshot *VenueSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved[venueSlug] = append(s.saved[venueSlug], snapshot)
+	return nil
+}
+
+func (s *countingSnapshotStore) LoadAll() (map[string]*VenueSnapshot, error) {
+	return nil, nil
+}
+
+func TestGetVenueInformation_PersistsOnlyFetchedData(t *testing.T) {
+	var failing atomic.Bool
+	server := flakyVenueServer(t, &failing)
+	store := &countingSnapshotStore{saved: map[string][]*VenueSnapshot{}}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewVenueProvider(server.URL, WithSnapshots(store, time.Hour), WithHTTPCache(time.Minute, 0))
+	provider.now = func() time.Time { return now }
+
+	// Responses served from the HTTP cache keep the time they were fetched and are not persisted again.
+	fetchedAt := now
+	for range 3 {
+		_, dynamicData, err := provider.GetVenueInformation(context.Background(), "venue-a")
+		assert.NoError(t, err)
+		assert.Equal(t, fetchedAt, dynamicData.FetchedAt)
+		now = now.Add(time.Second)
+	}
+	assert.NoError(t, provider.FlushSnapshots(context.Background()))
+	assert.Len(t, store.saved["venue-a"], 1)
+
+	// Once the cached responses expire, the data fetched again is persisted.
+	now = now.Add(time.Minute)
+	_, _, err := provider.GetVenueInformation(context.Background(), "venue-a")
+	assert.NoError(t, err)
+	assert.NoError(t, provider.FlushSnapshots(context.Background()))
+	if assert.Len(t, store.saved["venue-a"], 2) {
+		assert.Equal(t, now, store.saved["venue-a"][1].DynamicFetchedAt)
+	}
+}
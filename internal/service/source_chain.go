@@ -0,0 +1,58 @@
+package service
+
+import (
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// VenueSource retrieves the static and dynamic information of a venue.
+type VenueSource interface {
+	GetVenueInformation(ctx context.Context, venueSlug string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error)
+}
+
+// NamedSource is a VenueSource with the name used to report it in logs and traces.
+type NamedSource struct {
+	Name   string
+	Source VenueSource
+}
+
+// VenueSourceChain tries its venue sources in order and returns the first successful lookup.
+type VenueSourceChain struct {
+	sources []NamedSource
+}
+
+// NewVenueSourceChain creates a VenueSourceChain falling back through sources in the given order.
+func NewVenueSourceChain(sources ...NamedSource) *VenueSourceChain {
+	return &VenueSourceChain{sources: sources}
+}
+
+// GetVenueInformation returns the venue information from the first source that has it. If every
+// source fails, the errors of all sources are returned joined, so callers can still match them.
+func (c *VenueSourceChain) GetVenueInformation(ctx context.Context, venueSlug string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	if len(c.sources) == 0 {
+		return nil, nil, errors.New("no venue source configured")
+	}
+
+	var errs []error
+	for _, source := range c.sources {
+		staticData, dynamicData, err := source.Source.GetVenueInformation(ctx, venueSlug)
+		if err == nil {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("venue.source", source.Name))
+			return staticData, dynamicData, nil
+		}
+		logging.FromContext(ctx).DebugContext(ctx, "venue source failed",
+			slog.String("source", source.Name),
+			slog.String("venue_slug", venueSlug),
+			slog.String("error", err.Error()),
+		)
+		errs = append(errs, fmt.Errorf("%s source: %w", source.Name, err))
+	}
+	return nil, nil, errors.Join(errs...)
+}
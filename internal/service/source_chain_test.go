@@ -0,0 +1,60 @@
+package service
+
+import (
+	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSource is a VenueSource returning fixed results and counting its lookups.
+type stubSource struct {
+	static  *models.VenueStaticResponse
+	dynamic *models.VenueDynamicResponse
+	err     error
+	calls   int
+}
+
+func (s *stubSource) GetVenueInformation(context.Context, string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	s.calls++
+	return s.static, s.dynamic, s.err
+}
+
+func TestVenueSourceChain_FallsBack(t *testing.T) {
+	failing := &stubSource{err: errors.New("venue API down")}
+	fallback := &stubSource{static: &models.VenueStaticResponse{}, dynamic: &models.VenueDynamicResponse{}}
+	unused := &stubSource{}
+	chain := NewVenueSourceChain(
+		NamedSource{Name: "http", Source: failing},
+		NamedSource{Name: "file", Source: fallback},
+		NamedSource{Name: "config", Source: unused},
+	)
+
+	staticData, dynamicData, err := chain.GetVenueInformation(context.Background(), "venue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Same(t, fallback.static, staticData)
+	assert.Same(t, fallback.dynamic, dynamicData)
+	assert.Equal(t, []int{1, 1, 0}, []int{failing.calls, fallback.calls, unused.calls})
+}
+
+func TestVenueSourceChain_AllFail(t *testing.T) {
+	limited := &ratelimit.LimitExceededError{Key: "venue"}
+	chain := NewVenueSourceChain(
+		NamedSource{Name: "http", Source: &stubSource{err: limited}},
+		NamedSource{Name: "file", Source: &stubSource{err: ErrVenueNotFound}},
+	)
+
+	_, _, err := chain.GetVenueInformation(context.Background(), "venue")
+	assert.ErrorIs(t, err, ErrVenueNotFound)
+	var limitErr *ratelimit.LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Contains(t, err.Error(), "http source")
+
+	_, _, err = NewVenueSourceChain().GetVenueInformation(context.Background(), "venue")
+	assert.Error(t, err)
+}
@@ -1,55 +1,201 @@
 package service
 
 import (
+	"backend-wolt-go/internal/logging"
 	"backend-wolt-go/internal/models"
+	"backend-wolt-go/internal/ratelimit"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the spans for the venue API calls.
+var tracer = otel.Tracer("backend-wolt-go/internal/service")
+
+// Upstream endpoint names used when reporting venue API calls.
+const (
+	EndpointStatic  = "static"
+	EndpointDynamic = "dynamic"
+)
+
+// ErrUpstreamTimeout is returned when a venue API call exceeds the timeout set with WithTimeout.
+var ErrUpstreamTimeout = errors.New("venue API request timed out")
+
+// ErrUpstream is returned when the venue API cannot be reached or answers with an error or an
+// invalid body. Unknown venues are reported with ErrVenueNotFound instead.
+var ErrUpstream = errors.New("venue API request failed")
+
+// UpstreamRecorder receives the latency and outcome of every venue API call.
+type UpstreamRecorder interface {
+	ObserveUpstream(endpoint string, duration time.Duration, err error)
+	// ObserveCache records whether cached venue data could be served in place of the venue API.
+	ObserveCache(cache string, hit bool)
+	// ObserveFreshness records the freshness state of an HTTP cache lookup for the endpoint.
+	ObserveFreshness(endpoint, state string)
+}
+
+// Option configures optional dependencies of a VenueProvider.
+type Option func(*VenueProvider)
+
+// WithRecorder sets the recorder notified about every venue API call.
+func WithRecorder(recorder UpstreamRecorder) Option {
+	return func(v *VenueProvider) {
+		v.recorder = recorder
+	}
+}
+
+// UpstreamLimiter limits the rate of venue API calls per venue slug.
+type UpstreamLimiter interface {
+	Allow(ctx context.Context, key string) (ratelimit.Result, error)
+}
+
+// WithLimiter sets the limiter consulted before every request sent to the venue API. Responses
+// served from the HTTP cache do not count.
+func WithLimiter(limiter UpstreamLimiter) Option {
+	return func(v *VenueProvider) {
+		v.limiter = limiter
+	}
+}
+
+// WithSnapshots persists the last known good venue information to store and serves it, marked
+// stale, when the venue API fails and the data is not older than maxAge.
+func WithSnapshots(store SnapshotStore, maxAge time.Duration) Option {
+	return func(v *VenueProvider) {
+		v.snapshotStore = store
+		v.snapshotMaxAge = maxAge
+	}
+}
+
+// WithTimeout bounds every venue API call, including reading the response body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *VenueProvider) {
+		v.client.Timeout = timeout
+	}
+}
+
+// PopularityTracker counts the requests made for each venue slug.
+type PopularityTracker interface {
+	Add(venueSlug string)
+}
+
+// WithPopularityTracker sets the tracker notified about every venue lookup.
+func WithPopularityTracker(tracker PopularityTracker) Option {
+	return func(v *VenueProvider) {
+		v.tracker = tracker
+	}
+}
+
+// nopRecorder is used when no UpstreamRecorder is configured.
+type nopRecorder struct{}
+
+func (nopRecorder) ObserveUpstream(string, time.Duration, error) {}
+func (nopRecorder) ObserveCache(string, bool)                    {}
+func (nopRecorder) ObserveFreshness(string, string)              {}
+
 // VenueProvider is responsible for fetching static and dynamic venue information from a remote server.
 type VenueProvider struct {
-	client  *http.Client // HTTP client for making API calls.
-	baseURL string       // Base URL of the venue information API.
+	client    *http.Client      // HTTP client for making API calls.
+	baseURL   string            // Base URL of the venue information API.
+	recorder  UpstreamRecorder  // Receives latency and errors of venue API calls.
+	limiter   UpstreamLimiter   // Limits venue API calls per venue slug, if set.
+	httpCache *responseCache    // Caches venue API responses by URL, if set.
+	tracker   PopularityTracker // Counts lookups per venue slug, if set.
+
+	snapshotStore  SnapshotStore             // Persists the last known good venue information, if set.
+	snapshotMaxAge time.Duration             // Maximum age of snapshot data served after an upstream failure.
+	snapshotMu     sync.RWMutex              // Guards snapshots and persisting.
+	snapshots      map[string]*VenueSnapshot // Last known good venue information by venue slug.
+	persisting     map[string]bool           // Venue slugs whose snapshot is being persisted.
+	persistWG      sync.WaitGroup            // Tracks the background snapshot writers.
+	warmed         atomic.Bool               // Set once the snapshots were loaded from the store.
+
+	background     context.Context    // Cancelled by StopRevalidations to abort background revalidations.
+	stopBackground context.CancelFunc // Cancels background.
+	revalidateMu   sync.Mutex         // Orders the start of revalidations with StopRevalidations.
+	revalidateWG   sync.WaitGroup     // Tracks the background revalidations of the HTTP cache.
+
+	now func() time.Time
 }
 
-// NewVenueProvider creates a new instance of VenueProvider with the given base URL.
-func NewVenueProvider(baseURL string) *VenueProvider {
-	return &VenueProvider{
-		client:  &http.Client{},
-		baseURL: baseURL,
+// NewVenueProvider creates a new instance of VenueProvider with the given base URL and options.
+func NewVenueProvider(baseURL string, opts ...Option) *VenueProvider {
+	v := &VenueProvider{
+		client:   &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:  baseURL,
+		recorder: nopRecorder{},
+
+		snapshots:  make(map[string]*VenueSnapshot),
+		persisting: make(map[string]bool),
+		now:        time.Now,
 	}
+	v.background, v.stopBackground = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 // GetVenueInformation retrieves both static and dynamic information for a specific venue.
 // It makes two API calls: one for static data and another for dynamic data.
 func (v *VenueProvider) GetVenueInformation(ctx context.Context, venueSlug string) (*models.VenueStaticResponse, *models.VenueDynamicResponse, error) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("venue.slug", venueSlug))
+	if v.tracker != nil {
+		v.tracker.Add(venueSlug)
+	}
+
 	// Construct API URLs for static and dynamic data.
 	staticURL := fmt.Sprintf("%s/%s/static", v.baseURL, venueSlug)
-	dynamicURL := fmt.Sprintf("%s/%s/dynamic", v.baseURL, venueSlug)
+	dynamicURL := v.dynamicURL(venueSlug)
 
-	// Fetch static data.
+	// Fetch static data, falling back to the last known good snapshot.
 	staticData, err := v.FetchVenueStaticData(ctx, staticURL)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get static data: %w", err)
+		staticData, err = v.staleStatic(ctx, venueSlug, err)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get static data: %w", err)
+		}
 	}
 
-	// Fetch dynamic data.
+	// Fetch dynamic data, falling back to the last known good snapshot.
 	dynamicData, err := v.FetchVenueDynamicData(ctx, dynamicURL)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get dynamic data: %w", err)
+		dynamicData, err = v.staleDynamic(ctx, venueSlug, err)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get dynamic data: %w", err)
+		}
 	}
 
+	v.storeSnapshot(ctx, venueSlug, staticData, dynamicData)
 	return staticData, dynamicData, nil
 }
 
+// dynamicURL returns the venue API URL of the dynamic data of the venue.
+func (v *VenueProvider) dynamicURL(venueSlug string) string {
+	return fmt.Sprintf("%s/%s/dynamic", v.baseURL, venueSlug)
+}
+
 // FetchVenueStaticData fetches the static information of a venue from the given URL.
-func (v *VenueProvider) FetchVenueStaticData(ctx context.Context, url string) (*models.VenueStaticResponse, error) {
+func (v *VenueProvider) FetchVenueStaticData(ctx context.Context, url string) (_ *models.VenueStaticResponse, err error) {
+	ctx, span := tracer.Start(ctx, "VenueProvider.FetchVenueStaticData", trace.WithAttributes(attribute.String("url.full", url)))
+	defer func() { endSpan(span, err) }()
+
 	// Call the API and get the response bytes.
-	respByte, err := v.callAPI(ctx, url)
+	respByte, fetchedAt, err := v.callAPI(ctx, EndpointStatic, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call static route: %w", err)
 	}
@@ -58,23 +204,27 @@ func (v *VenueProvider) FetchVenueStaticData(ctx context.Context, url string) (*
 	staticDataResponse := &models.VenueStaticResponse{}
 	err = json.Unmarshal(respByte, staticDataResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+		return nil, fmt.Errorf("%w: failed to decode JSON response: %w", ErrUpstream, err)
 	}
 
 	// Check if the response contains valid data.
 	if staticDataResponse.VenueRaw == nil {
 		var errorResponse models.ServerError
 		_ = json.Unmarshal(respByte, &errorResponse)
-		return nil, fmt.Errorf("failed to get response: %v", errorResponse)
+		return nil, fmt.Errorf("%w: failed to get response: %v", ErrUpstream, errorResponse)
 	}
 
+	staticDataResponse.FetchedAt = fetchedAt
 	return staticDataResponse, nil
 }
 
 // FetchVenueDynamicData fetches the dynamic information of a venue from the given URL.
-func (v *VenueProvider) FetchVenueDynamicData(ctx context.Context, url string) (*models.VenueDynamicResponse, error) {
+func (v *VenueProvider) FetchVenueDynamicData(ctx context.Context, url string) (_ *models.VenueDynamicResponse, err error) {
+	ctx, span := tracer.Start(ctx, "VenueProvider.FetchVenueDynamicData", trace.WithAttributes(attribute.String("url.full", url)))
+	defer func() { endSpan(span, err) }()
+
 	// Call the API and get the response bytes.
-	respByte, err := v.callAPI(ctx, url)
+	respByte, fetchedAt, err := v.callAPI(ctx, EndpointDynamic, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call dynamic route: %w", err)
 	}
@@ -83,39 +233,140 @@ func (v *VenueProvider) FetchVenueDynamicData(ctx context.Context, url string) (
 	dynamicDataResponse := &models.VenueDynamicResponse{}
 	err = json.Unmarshal(respByte, dynamicDataResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+		return nil, fmt.Errorf("%w: failed to decode JSON response: %w", ErrUpstream, err)
 	}
 
 	// Check if the response contains valid data.
 	if dynamicDataResponse.VenueRaw == nil {
 		var errorResponse models.ServerError
 		_ = json.Unmarshal(respByte, &errorResponse)
-		return nil, fmt.Errorf("failed to get response: %v", errorResponse)
+		return nil, fmt.Errorf("%w: failed to get response: %v", ErrUpstream, errorResponse)
 	}
 
+	dynamicDataResponse.FetchedAt = fetchedAt
 	return dynamicDataResponse, nil
 }
 
-// callAPI makes an HTTP GET request to the given URL and returns the response body as a byte slice.
-func (v *VenueProvider) callAPI(ctx context.Context, url string) ([]byte, error) {
+// Ping checks that the venue information API is reachable. Any response below 500 counts as
+// reachable, since the base URL itself is not a venue resource and may answer with 404.
+func (v *VenueProvider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("venue API unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return errors.New("venue API unhealthy: " + resp.Status)
+	}
+	return nil
+}
+
+// callAPI returns the body of the venue API response for the given URL and the time it was
+// fetched from the venue API, going through the HTTP cache when it is enabled.
+func (v *VenueProvider) callAPI(ctx context.Context, endpoint, url string) ([]byte, time.Time, error) {
+	if v.httpCache != nil {
+		return v.cachedCall(ctx, endpoint, url)
+	}
+	resp, err := v.doRequest(ctx, endpoint, url, nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return resp.body, v.now(), nil
+}
+
+// venueSlug returns the venue slug of a venue API URL, which keys the upstream limiter.
+func (v *VenueProvider) venueSlug(url string) string {
+	venueSlug, _, _ := strings.Cut(strings.TrimPrefix(url, v.baseURL+"/"), "/")
+	return venueSlug
+}
+
+// upstreamResponse is a successful venue API response.
+type upstreamResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// doRequest makes an HTTP GET request to the given URL with the extra headers. A 304 Not Modified
+// is accepted only for conditional requests. Every call is reported to the recorder, and failed
+// calls are logged with the upstream status code and latency.
+func (v *VenueProvider) doRequest(ctx context.Context, endpoint, url string, header http.Header) (_ *upstreamResponse, err error) {
+	// Protect the upstream from being hammered for a single venue.
+	if v.limiter != nil {
+		venueSlug := v.venueSlug(url)
+		if _, err := v.limiter.Allow(ctx, venueSlug); err != nil {
+			return nil, fmt.Errorf("upstream calls for venue %s limited: %w", venueSlug, err)
+		}
+	}
+
+	logger := logging.FromContext(ctx)
+	start := time.Now()
+	defer func() { v.recorder.ObserveUpstream(endpoint, time.Since(start), err) }()
+
 	// Create a new HTTP request with the provided context and URL.
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	// Execute the HTTP request.
 	resp, err := v.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		logger.ErrorContext(ctx, "upstream request failed",
+			slog.String("url", url),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("error", err.Error()),
+		)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && ctx.Err() == nil {
+			return nil, fmt.Errorf("HTTP request failed: %w", ErrUpstreamTimeout)
+		}
+		return nil, fmt.Errorf("%w: HTTP request failed: %w", ErrUpstream, err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response status code indicates success.
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("unexpected status code: " + resp.Status)
+	conditional := req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+	if resp.StatusCode != http.StatusOK && !(conditional && resp.StatusCode == http.StatusNotModified) {
+		logger.WarnContext(ctx, "upstream returned unexpected status",
+			slog.String("url", url),
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("latency", time.Since(start)),
+		)
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("venue API returned %s: %w", resp.Status, ErrVenueNotFound)
+		}
+		return nil, fmt.Errorf("%w: unexpected status code: %s", ErrUpstream, resp.Status)
+	}
+
+	// Read the response body.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response body: %w", ErrUpstream, err)
 	}
 
-	// Read and return the response body.
-	return io.ReadAll(resp.Body)
+	logger.DebugContext(ctx, "upstream request completed",
+		slog.String("url", url),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", time.Since(start)),
+	)
+	return &upstreamResponse{status: resp.StatusCode, header: resp.Header, body: body}, nil
+}
+
+// endSpan marks the span as failed when err is set and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
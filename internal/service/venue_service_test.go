@@ -1,11 +1,22 @@
 package service
 
 import (
+	"backend-wolt-go/internal/logging"
+	"backend-wolt-go/internal/ratelimit"
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestGetVenueInformation_Success(t *testing.T) {
@@ -49,3 +60,194 @@ func TestGetVenueInformation_Error(t *testing.T) {
 		t.Error("expected error, got nil")
 	}
 }
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Reachable", status: http.StatusOK},
+		{name: "Not found is reachable", status: http.StatusNotFound},
+		{name: "Server error", status: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewVenueProvider(server.URL).Ping(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected error state: %v", err)
+			}
+		})
+	}
+}
+
+func TestPing_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	if err := NewVenueProvider(server.URL).Ping(context.Background()); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+type fakeRecorder struct {
+	calls     []string
+	errors    int
+	cacheHits map[bool]int
+	freshness map[string]int
+}
+
+func (f *fakeRecorder) ObserveFreshness(endpoint, state string) {
+	if f.freshness == nil {
+		f.freshness = map[string]int{}
+	}
+	f.freshness[state]++
+}
+
+func (f *fakeRecorder) ObserveCache(cache string, hit bool) {
+	if f.cacheHits == nil {
+		f.cacheHits = map[bool]int{}
+	}
+	f.cacheHits[hit]++
+}
+
+func (f *fakeRecorder) ObserveUpstream(endpoint string, duration time.Duration, err error) {
+	f.calls = append(f.calls, endpoint)
+	if err != nil {
+		f.errors++
+	}
+}
+
+func TestGetVenueInformation_Recorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.String(), "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	recorder := &fakeRecorder{}
+	_, _, err := NewVenueProvider(server.URL, WithRecorder(recorder)).GetVenueInformation(context.Background(), "test-slug")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(recorder.calls) != 2 || recorder.calls[0] != EndpointStatic || recorder.calls[1] != EndpointDynamic {
+		t.Errorf("unexpected recorded calls: %v", recorder.calls)
+	}
+	if recorder.errors != 1 {
+		t.Errorf("expected 1 recorded error, got %d", recorder.errors)
+	}
+}
+
+func TestGetVenueInformation_PropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if strings.Contains(r.URL.String(), "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 15, "delivery_pricing": {"base_price": 5}}}}`))
+	}))
+	defer server.Close()
+
+	if _, _, err := NewVenueProvider(server.URL).GetVenueInformation(context.Background(), "test-slug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(traceparents) != 2 || traceparents[0] == "" || traceparents[1] == "" {
+		t.Errorf("expected traceparent on every upstream request, got %v", traceparents)
+	}
+}
+
+func TestCallAPI_LogsUpstreamFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger, err := logging.New("info", &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := logging.WithLogger(context.Background(), logger.With(slog.String("request_id", "req-1")))
+
+	if _, _, err := NewVenueProvider(server.URL).GetVenueInformation(ctx, "test-slug"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{`"request_id":"req-1"`, `"status":503`, `"latency"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected log to contain %s, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestGetVenueInformation_RateLimited(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if strings.Contains(r.URL.String(), "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 15, "delivery_pricing": {"base_price": 5}}}}`))
+	}))
+	defer server.Close()
+
+	// Every request sent to the venue API takes a token: one for the static and one for the dynamic data.
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 2}, "venue:")
+	venueProvider := NewVenueProvider(server.URL, WithLimiter(limiter))
+
+	if _, _, err := venueProvider.GetVenueInformation(context.Background(), "test-slug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err := venueProvider.GetVenueInformation(context.Background(), "test-slug")
+	var limitErr *ratelimit.LimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the limited request not to reach upstream, got %d calls", calls)
+	}
+}
+
+func TestGetVenueInformation_CacheHitsNotRateLimited(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Cache-Control", "max-age=60")
+		if strings.Contains(r.URL.String(), "static") {
+			w.Write([]byte(`{"venue_raw": {"location": {"coordinates": [24.9354, 60.1699]}}}`))
+			return
+		}
+		w.Write([]byte(`{"venue_raw": {"delivery_specs": {"order_minimum_no_surcharge": 15, "delivery_pricing": {"base_price": 5}}}}`))
+	}))
+	defer server.Close()
+
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 2}, "venue:")
+	venueProvider := NewVenueProvider(server.URL, WithLimiter(limiter), WithHTTPCache(0, 0))
+
+	for i := 0; i < 5; i++ {
+		if _, _, err := venueProvider.GetVenueInformation(context.Background(), "test-slug"); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected cached responses to be served without upstream calls, got %d calls", calls)
+	}
+}
@@ -0,0 +1,95 @@
+package topk
+
+import (
+	"sort"
+	"sync"
+)
+
+// counter is the estimated count of a tracked key.
+type counter struct {
+	count int
+	err   int // Maximum overestimation of count, inherited from the evicted key.
+}
+
+// Sketch tracks the most frequent keys of a stream in bounded memory using the Space-Saving
+// algorithm: at most capacity keys are tracked, and a new key replaces the least frequent one.
+// It is safe for concurrent use.
+type Sketch struct {
+	mu       sync.Mutex
+	capacity int
+	counters map[string]*counter
+}
+
+// New creates a Sketch tracking at most capacity keys. A capacity a few times larger than the
+// number of keys queried with Top gives accurate results for skewed streams.
+func New(capacity int) *Sketch {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Sketch{
+		capacity: capacity,
+		counters: make(map[string]*counter, capacity),
+	}
+}
+
+// Add records one occurrence of key.
+func (s *Sketch) Add(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counters[key]; ok {
+		c.count++
+		return
+	}
+	if len(s.counters) < s.capacity {
+		s.counters[key] = &counter{count: 1}
+		return
+	}
+
+	// Replace the least frequent key; the newcomer inherits its count as the error bound.
+	minKey, minCounter := "", (*counter)(nil)
+	for k, c := range s.counters {
+		if minCounter == nil || c.count < minCounter.count {
+			minKey, minCounter = k, c
+		}
+	}
+	delete(s.counters, minKey)
+	s.counters[key] = &counter{count: minCounter.count + 1, err: minCounter.count}
+}
+
+// Top returns up to k keys ordered from the most to the least frequent.
+func (s *Sketch) Top(k int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.counters))
+	for key := range s.counters {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := s.counters[keys[i]], s.counters[keys[j]]
+		if ci.count != cj.count {
+			return ci.count > cj.count
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > k {
+		keys = keys[:k]
+	}
+	return keys
+}
+
+// Decay halves every count and forgets keys whose count drops to zero, so that the sketch
+// follows the recent popularity of the keys rather than their all-time totals.
+func (s *Sketch) Decay() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, c := range s.counters {
+		c.count /= 2
+		c.err /= 2
+		if c.count == 0 {
+			delete(s.counters, key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package topk
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSketch_Top(t *testing.T) {
+	sketch := New(10)
+	for key, count := range map[string]int{"a": 5, "b": 3, "c": 8, "d": 1} {
+		for i := 0; i < count; i++ {
+			sketch.Add(key)
+		}
+	}
+
+	assert.Equal(t, []string{"c", "a"}, sketch.Top(2))
+	assert.Equal(t, []string{"c", "a", "b", "d"}, sketch.Top(10))
+}
+
+func TestSketch_BoundedCapacity(t *testing.T) {
+	sketch := New(10)
+
+	// Keys seen more often than once per capacity-th of the stream survive a long tail of rare ones.
+	for i := 0; i < 100; i++ {
+		sketch.Add("hot")
+		if i%2 == 0 {
+			sketch.Add("warm")
+		}
+		sketch.Add(fmt.Sprintf("rare-%d", i))
+	}
+
+	assert.Len(t, sketch.counters, 10)
+	assert.Equal(t, []string{"hot", "warm"}, sketch.Top(2))
+}
+
+func TestSketch_Decay(t *testing.T) {
+	sketch := New(10)
+	for i := 0; i < 4; i++ {
+		sketch.Add("old")
+	}
+	sketch.Add("once")
+
+	sketch.Decay()
+	for i := 0; i < 3; i++ {
+		sketch.Add("new")
+	}
+
+	assert.Equal(t, []string{"new", "old"}, sketch.Top(10))
+}
+
+func TestSketch_Concurrent(t *testing.T) {
+	sketch := New(5)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sketch.Add("venue")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 800, sketch.counters["venue"].count)
+}
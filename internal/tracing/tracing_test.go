@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"backend-wolt-go/internal/models"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_Stdout(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := setup(context.Background(), models.TracingConfig{Enabled: true, Exporter: ExporterStdout}, &buf)
+	assert.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"test-span"`)
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), models.TracingConfig{Enabled: false, Exporter: ExporterOTLP})
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), models.TracingConfig{Enabled: true, Exporter: "zipkin"})
+	assert.Error(t, err)
+}
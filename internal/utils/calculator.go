@@ -2,25 +2,37 @@ package utils
 
 import (
 	"backend-wolt-go/internal/models"
-	"fmt"
+	"errors"
 	"math"
 )
 
+// ErrDeliveryNotPossible is returned when the distance is not covered by any of the venue's distance ranges.
+var ErrDeliveryNotPossible = errors.New("delivery is not possible, distance too long")
+
+// ReasonDistanceTooLong is the reason code reported for ErrDeliveryNotPossible.
+const ReasonDistanceTooLong = "distance_too_long"
+
 // CalculateDeliveryFee calculates the delivery fee based on the distance,
 // base price, and a range of distance-based pricing rules.
 // Returns the delivery fee or an error if the distance exceeds the supported range.
 func CalculateDeliveryFee(distance int, basePrice int, distanceRange []models.DistanceRange) (int, error) {
-	var fee int
-	for _, rangeData := range distanceRange {
+	i, ok := FindDistanceRange(distance, distanceRange)
+	if !ok {
+		return 0, ErrDeliveryNotPossible
+	}
+	rangeData := distanceRange[i]
+	return basePrice + rangeData.A + int(rangeData.B*float64(distance)/10), nil
+}
+
+// FindDistanceRange returns the index of the first distance range containing the distance
+// and whether such a range exists.
+func FindDistanceRange(distance int, distanceRange []models.DistanceRange) (int, bool) {
+	for i, rangeData := range distanceRange {
 		if distance >= rangeData.Min && distance < rangeData.Max {
-			fee = basePrice + rangeData.A + int(rangeData.B*float64(distance)/10)
-			break
+			return i, true
 		}
 	}
-	if fee == 0 {
-		return 0, fmt.Errorf("delivery is not possible, distance too long")
-	}
-	return fee, nil
+	return -1, false
 }
 
 // CalculateSmallOrderSurcharge calculates the surcharge for small orders
@@ -56,6 +68,8 @@ func CalculateDistance(lat1, lon1, lat2, lon2 float64) int {
 
 	// Apply the haversine formula to calculate the distance.
 	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1Rad)*math.Cos(lat2Rad)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	// Rounding can push a slightly above 1 for antipodal points, which would make the root below NaN.
+	a = math.Min(a, 1)
 	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
 
 	return int(math.Round(EarthRadius * c))
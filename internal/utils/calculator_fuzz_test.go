@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"backend-wolt-go/internal/models"
+	"math"
+	"testing"
+)
+
+// The seed corpus of the fuzz targets is checked in under testdata/fuzz. Run a target with
+// go test ./internal/utils -run '^$' -fuzz FuzzCalculateDistance -fuzztime 30s
+
+func FuzzCalculateDistance(f *testing.F) {
+	f.Add(60.17012143, 24.92813512, 60.17094, 24.93087)
+	f.Add(0.0, 0.0, 0.0, 180.0)
+	f.Add(90.0, 0.0, -90.0, 0.0)
+	f.Add(-33.8688, 151.2093, 51.5074, -0.1278)
+
+	f.Fuzz(func(t *testing.T, lat1, lon1, lat2, lon2 float64) {
+		if !validCoordinates(lat1, lon1) || !validCoordinates(lat2, lon2) {
+			t.Skip()
+		}
+
+		distance := CalculateDistance(lat1, lon1, lat2, lon2)
+		if distance < 0 || distance > maxEarthDistance {
+			t.Fatalf("distance %d out of [0, %d]", distance, maxEarthDistance)
+		}
+		if reverse := CalculateDistance(lat2, lon2, lat1, lon1); reverse != distance {
+			t.Fatalf("distance is not symmetric: %d != %d", distance, reverse)
+		}
+		if self := CalculateDistance(lat1, lon1, lat1, lon1); self != 0 {
+			t.Fatalf("distance to itself is %d", self)
+		}
+	})
+}
+
+func FuzzCalculateDeliveryFee(f *testing.F) {
+	f.Add(177, 190, 0, 500, 100, 0.0)
+	f.Add(500, 190, 0, 500, 100, 1.0)
+	f.Add(1999, 0, 1000, 2000, 200, 1.5)
+	f.Add(2000, 190, 0, 2000, 0, 0.0)
+
+	f.Fuzz(func(t *testing.T, distance, basePrice, boundary, end, a int, b float64) {
+		// Two contiguous ranges ending at end, with non-negative pricing factors.
+		if distance < 0 || basePrice <= 0 || boundary <= 0 || end <= boundary || a < 0 ||
+			b < 0 || b > 100 || math.IsNaN(b) || end > 1_000_000 || basePrice > 1_000_000 || a > 1_000_000 {
+			t.Skip()
+		}
+		ranges := []models.DistanceRange{
+			{Min: 0, Max: boundary, A: 0, B: 0},
+			{Min: boundary, Max: end, A: a, B: b},
+			{Min: end, Max: 0},
+		}
+
+		fee, err := CalculateDeliveryFee(distance, basePrice, ranges)
+		_, inRange := FindDistanceRange(distance, ranges)
+		if inRange != (distance < end) {
+			t.Fatalf("distance %d: in range %t, want %t", distance, inRange, distance < end)
+		}
+		if !inRange {
+			if err == nil {
+				t.Fatalf("distance %d beyond %d was priced at %d", distance, end, fee)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("distance %d within %d was rejected: %v", distance, end, err)
+		}
+		if fee < basePrice {
+			t.Fatalf("fee %d below the base price %d", fee, basePrice)
+		}
+		// The total price of the largest cart value the endpoints accept does not overflow.
+		if total := CalculateTotalPrice(math.MaxInt32, 0, fee); total < math.MaxInt32 || total-fee != math.MaxInt32 {
+			t.Fatalf("total price of the largest cart with fee %d overflowed to %d", fee, total)
+		}
+	})
+}
+
+// maxEarthDistance is half of the Earth's circumference in meters, the longest great-circle distance.
+const maxEarthDistance = 20015087
+
+// validCoordinates reports whether the latitude and longitude are finite and in range.
+func validCoordinates(lat, lon float64) bool {
+	return !math.IsNaN(lat) && !math.IsNaN(lon) && lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
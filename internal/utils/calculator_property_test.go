@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"backend-wolt-go/internal/models"
+	"math/rand/v2"
+	"sort"
+	"testing"
+)
+
+// propertyRuns is the number of random cases checked by each property test. The generators are
+// seeded so that failures are reproducible.
+const propertyRuns = 1000
+
+// randomPoint returns a random valid latitude and longitude.
+func randomPoint(r *rand.Rand) (float64, float64) {
+	return r.Float64()*180 - 90, r.Float64()*360 - 180
+}
+
+func TestCalculateDistance_Properties(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < propertyRuns; i++ {
+		lat1, lon1 := randomPoint(r)
+		lat2, lon2 := randomPoint(r)
+		lat3, lon3 := randomPoint(r)
+
+		ab := CalculateDistance(lat1, lon1, lat2, lon2)
+		if ba := CalculateDistance(lat2, lon2, lat1, lon1); ab != ba {
+			t.Fatalf("(%v, %v) to (%v, %v): not symmetric, %d != %d", lat1, lon1, lat2, lon2, ab, ba)
+		}
+		if aa := CalculateDistance(lat1, lon1, lat1, lon1); aa != 0 {
+			t.Fatalf("(%v, %v) to itself: got %d", lat1, lon1, aa)
+		}
+		if ab < 0 || ab > maxEarthDistance {
+			t.Fatalf("(%v, %v) to (%v, %v): %d out of [0, %d]", lat1, lon1, lat2, lon2, ab, maxEarthDistance)
+		}
+
+		// Triangle inequality, allowing one meter of rounding per distance.
+		bc := CalculateDistance(lat2, lon2, lat3, lon3)
+		ac := CalculateDistance(lat1, lon1, lat3, lon3)
+		if ac > ab+bc+2 {
+			t.Fatalf("triangle inequality violated: %d > %d + %d", ac, ab, bc)
+		}
+	}
+}
+
+// randomMonotonicRanges returns contiguous distance ranges whose A and B never decrease, ending
+// with the Max 0 range that marks the end of the delivery area, and the distance where they end.
+func randomMonotonicRanges(r *rand.Rand) ([]models.DistanceRange, int) {
+	var ranges []models.DistanceRange
+	minDistance, a, b := 0, 0, 0.0
+	for n := 1 + r.IntN(5); n > 0; n-- {
+		maxDistance := minDistance + 1 + r.IntN(2000)
+		ranges = append(ranges, models.DistanceRange{Min: minDistance, Max: maxDistance, A: a, B: b})
+		minDistance = maxDistance
+		a += r.IntN(300)
+		b += float64(r.IntN(4)) / 2
+	}
+	return append(ranges, models.DistanceRange{Min: minDistance, Max: 0}), minDistance
+}
+
+func TestCalculateDeliveryFee_NonDecreasingWithDistance(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for i := 0; i < propertyRuns; i++ {
+		ranges, end := randomMonotonicRanges(r)
+		basePrice := 1 + r.IntN(500)
+
+		distances := make([]int, 20)
+		for j := range distances {
+			distances[j] = r.IntN(end + 500)
+		}
+		sort.Ints(distances)
+
+		previous := 0
+		for _, distance := range distances {
+			fee, err := CalculateDeliveryFee(distance, basePrice, ranges)
+			if distance >= end {
+				if err == nil {
+					t.Fatalf("ranges %v: distance %d beyond %d was priced at %d", ranges, distance, end, fee)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("ranges %v: distance %d rejected: %v", ranges, distance, err)
+			}
+			if fee < previous {
+				t.Fatalf("ranges %v: fee decreased to %d at distance %d, was %d", ranges, fee, distance, previous)
+			}
+			previous = fee
+		}
+	}
+}
+
+func TestCalculateTotalPrice_SumsComponents(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for i := 0; i < propertyRuns; i++ {
+		cartValue := 1 + r.IntN(10000)
+		orderMinimum := r.IntN(5000)
+		deliveryFee := r.IntN(2000)
+
+		surcharge := CalculateSmallOrderSurcharge(cartValue, orderMinimum)
+		if surcharge < 0 || cartValue+surcharge < orderMinimum || (cartValue >= orderMinimum && surcharge != 0) {
+			t.Fatalf("cart %d, minimum %d: unexpected surcharge %d", cartValue, orderMinimum, surcharge)
+		}
+		if total := CalculateTotalPrice(cartValue, surcharge, deliveryFee); total != cartValue+surcharge+deliveryFee {
+			t.Fatalf("total %d != %d + %d + %d", total, cartValue, surcharge, deliveryFee)
+		}
+	}
+}
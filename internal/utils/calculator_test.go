@@ -21,6 +21,19 @@ func TestCalculateDeliveryFee_Success(t *testing.T) {
 	}
 }
 
+func TestCalculateDeliveryFee_Free(t *testing.T) {
+	distanceRanges := []models.DistanceRange{
+		{Min: 0, Max: 1000, A: 0, B: 0},
+	}
+	fee, err := CalculateDeliveryFee(500, 0, distanceRanges)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fee != 0 {
+		t.Errorf("expected fee to be 0, got %d", fee)
+	}
+}
+
 func TestCalculateDeliveryFee_OutOfRange(t *testing.T) {
 	distance := 50
 	basePrice := 10
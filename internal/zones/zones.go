@@ -0,0 +1,107 @@
+package zones
+
+import (
+	"backend-wolt-go/internal/geo"
+	"backend-wolt-go/internal/models"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Zone is a delivery area of a venue with its own fee components.
+type Zone struct {
+	Name string
+	A    int     // Constant factor for the delivery fee.
+	B    float64 // Multiplier factor for the delivery fee.
+	Area geo.Area
+}
+
+// Fee returns the delivery fee of an order in the zone, following the distance range formula.
+func (z Zone) Fee(basePrice, distance int) int {
+	return basePrice + z.A + int(z.B*float64(distance)/10)
+}
+
+// Store holds the delivery zones of the venues. It is read-only once loaded and safe for
+// concurrent use.
+type Store struct {
+	zones map[string][]Zone // Zones by venue slug, in the order they are checked.
+}
+
+// featureCollection is a GeoJSON FeatureCollection of zones.
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Properties struct {
+			VenueSlug string  `json:"venue_slug"`
+			Name      string  `json:"name"`
+			A         int     `json:"a"`
+			B         float64 `json:"b"`
+		} `json:"properties"`
+		Geometry json.RawMessage `json:"geometry"`
+	} `json:"features"`
+}
+
+// Load builds a Store from the zones of the GeoJSON file, if any, followed by the zones defined
+// inline in the configuration.
+func Load(config models.DeliveryZonesConfig) (*Store, error) {
+	s := &Store{zones: make(map[string][]Zone)}
+
+	if config.File != "" {
+		if err := s.loadFile(config.File); err != nil {
+			return nil, err
+		}
+	}
+
+	for venueSlug, zoneConfigs := range config.Venues {
+		for _, zoneConfig := range zoneConfigs {
+			geometry, err := json.Marshal(zoneConfig.Geometry)
+			if err != nil {
+				return nil, fmt.Errorf("zone %q of venue %s: %w", zoneConfig.Name, venueSlug, err)
+			}
+			area, err := geo.ParseGeometry(geometry)
+			if err != nil {
+				return nil, fmt.Errorf("zone %q of venue %s: %w", zoneConfig.Name, venueSlug, err)
+			}
+			s.zones[venueSlug] = append(s.zones[venueSlug], Zone{Name: zoneConfig.Name, A: zoneConfig.A, B: zoneConfig.B, Area: area})
+		}
+	}
+	return s, nil
+}
+
+// loadFile adds the zones of a GeoJSON FeatureCollection file.
+func (s *Store) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read zone file: %w", err)
+	}
+	var collection featureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return fmt.Errorf("could not decode zone file: %w", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return fmt.Errorf("zone file is a %q, want a FeatureCollection", collection.Type)
+	}
+
+	for i, feature := range collection.Features {
+		properties := feature.Properties
+		if properties.VenueSlug == "" {
+			return fmt.Errorf("zone %d of the zone file has no venue_slug", i)
+		}
+		area, err := geo.ParseGeometry(feature.Geometry)
+		if err != nil {
+			return fmt.Errorf("zone %q of venue %s: %w", properties.Name, properties.VenueSlug, err)
+		}
+		s.zones[properties.VenueSlug] = append(s.zones[properties.VenueSlug], Zone{Name: properties.Name, A: properties.A, B: properties.B, Area: area})
+	}
+	return nil
+}
+
+// Match returns the first zone of the venue containing the location.
+func (s *Store) Match(venueSlug string, lat, lon float64) (Zone, bool) {
+	for _, zone := range s.zones[venueSlug] {
+		if zone.Area.Contains(lon, lat) {
+			return zone, true
+		}
+	}
+	return Zone{}, false
+}
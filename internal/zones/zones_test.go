@@ -0,0 +1,90 @@
+package zones
+
+import (
+	"backend-wolt-go/internal/models"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_File(t *testing.T) {
+	store, err := Load(models.DeliveryZonesConfig{File: "testdata/zones.geojson"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	zone, ok := store.Match("venue", 60.5, 24.5)
+	assert.True(t, ok)
+	assert.Equal(t, "centre", zone.Name)
+
+	zone, ok = store.Match("venue", 60.5, 27.5)
+	assert.True(t, ok)
+	assert.Equal(t, "islands", zone.Name)
+	assert.Equal(t, 400, zone.A)
+	assert.Equal(t, 1.0, zone.B)
+
+	_, ok = store.Match("venue", 60.5, 26.5)
+	assert.False(t, ok, "between the islands")
+	_, ok = store.Match("other-venue", 60.5, 24.5)
+	assert.False(t, ok, "zones belong to their venue")
+}
+
+func TestLoad_FileZonesComeFirst(t *testing.T) {
+	store, err := Load(models.DeliveryZonesConfig{
+		File: "testdata/zones.geojson",
+		Venues: map[string][]models.ZoneConfig{
+			"venue": {{
+				Name: "inline",
+				A:    100,
+				Geometry: map[string]any{
+					"type":        "Polygon",
+					"coordinates": []any{[]any{[]any{24.0, 60.0}, []any{30.0, 60.0}, []any{30.0, 61.0}, []any{24.0, 61.0}, []any{24.0, 60.0}}},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// The first matching zone wins, so the inline zone only applies where the file has none.
+	zone, _ := store.Match("venue", 60.5, 24.5)
+	assert.Equal(t, "centre", zone.Name)
+	zone, _ = store.Match("venue", 60.5, 26.5)
+	assert.Equal(t, "inline", zone.Name)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name   string
+		config models.DeliveryZonesConfig
+	}{
+		{"MissingFile", models.DeliveryZonesConfig{File: filepath.Join(dir, "missing.geojson")}},
+		{"InvalidJSON", models.DeliveryZonesConfig{File: write("invalid.geojson", `{"type":`)}},
+		{"NotACollection", models.DeliveryZonesConfig{File: write("feature.geojson", `{"type": "Feature"}`)}},
+		{"MissingVenueSlug", models.DeliveryZonesConfig{File: write("no-slug.geojson", `{"type": "FeatureCollection", "features": [
+			{"properties": {"name": "zone"}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}
+		]}`)}},
+		{"InvalidInlineGeometry", models.DeliveryZonesConfig{Venues: map[string][]models.ZoneConfig{
+			"venue": {{Name: "zone", Geometry: map[string]any{"type": "Point", "coordinates": []any{0.0, 0.0}}}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.config)
+			assert.Error(t, err)
+		})
+	}
+}
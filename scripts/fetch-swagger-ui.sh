@@ -0,0 +1,18 @@
+#!/bin/sh
+# Fetches the Swagger UI files served by the documentation page at /docs into the directory
+# configured in openapi.docs_assets_dir. The version is pinned, and npm checks the package
+# against the integrity hash published in the registry.
+set -eu
+
+VERSION=5.17.14
+DEST=${1:-web/swagger-ui}
+
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+npm pack --silent --pack-destination "$tmp" "swagger-ui-dist@$VERSION" >/dev/null
+tar -xzf "$tmp/swagger-ui-dist-$VERSION.tgz" -C "$tmp"
+
+mkdir -p "$DEST"
+cp "$tmp/package/swagger-ui.css" "$tmp/package/swagger-ui-bundle.js" "$DEST/"
+echo "swagger-ui-dist $VERSION written to $DEST"